@@ -0,0 +1,33 @@
+package bitriseapi
+
+// EnvironmentItem is a single environment variable to be passed
+// to the Bitrise Trigger API, in the format it expects.
+type EnvironmentItem struct {
+	MappedTo string `json:"mapped_to"`
+	Value    string `json:"value"`
+	IsExpand bool   `json:"is_expand"`
+}
+
+// BuildParamsModel ...
+type BuildParamsModel struct {
+	Branch        string            `json:"branch,omitempty"`
+	Tag           string            `json:"tag,omitempty"`
+	CommitHash    string            `json:"commit_hash,omitempty"`
+	CommitMessage string            `json:"commit_message,omitempty"`
+	WorkflowID    string            `json:"workflow_id,omitempty"`
+	Environments  []EnvironmentItem `json:"environments,omitempty"`
+}
+
+// TriggerAPIParamsModel ...
+type TriggerAPIParamsModel struct {
+	BuildParams BuildParamsModel `json:"build_params"`
+}
+
+// TriggerAPIResponseModel ...
+type TriggerAPIResponseModel struct {
+	Status    string `json:"status"`
+	Message   string `json:"message"`
+	Service   string `json:"service"`
+	AppSlug   string `json:"app_slug"`
+	BuildSlug string `json:"build_slug"`
+}
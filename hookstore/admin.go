@@ -0,0 +1,213 @@
+package hookstore
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bitrise-io/bitrise-webhooks/bitriseapi"
+	hookCommon "github.com/bitrise-io/bitrise-webhooks/service/hook/common"
+)
+
+// adminTokenEnvKey is the env var holding the shared secret RedeliverHandler
+// requires callers to present (as a Bearer token) before it will replay a
+// stored HookTask and fire a real Trigger API call from it.
+const adminTokenEnvKey = "HOOKSTORE_ADMIN_TOKEN"
+
+// AdminTokenFromEnv reads the token RedeliverHandler should require, from
+// HOOKSTORE_ADMIN_TOKEN. An empty result means redelivery can't be
+// authorized, not that it's open to everyone - see isAuthorizedAdminRequest.
+func AdminTokenFromEnv() string {
+	return os.Getenv(adminTokenEnvKey)
+}
+
+// isAuthorizedAdminRequest reports whether r carries adminToken as an
+// `Authorization: Bearer <token>` header. An unset adminToken always fails
+// closed, so forgetting to configure HOOKSTORE_ADMIN_TOKEN disables the
+// endpoint instead of leaving it open.
+func isAuthorizedAdminRequest(r *http.Request, adminToken string) bool {
+	if adminToken == "" {
+		return false
+	}
+	const bearerPrefix = "Bearer "
+	authHeader := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authHeader, bearerPrefix) {
+		return false
+	}
+	presented := strings.TrimPrefix(authHeader, bearerPrefix)
+	return subtle.ConstantTimeCompare([]byte(presented), []byte(adminToken)) == 1
+}
+
+// redeliverPathPrefix and redeliverPathSuffix bracket the {uuid} segment of
+// the admin redeliver endpoint: POST /admin/hooks/{uuid}/redeliver.
+const (
+	redeliverPathPrefix = "/admin/hooks/"
+	redeliverPathSuffix = "/redeliver"
+)
+
+// RecordAndTransformRequest saves a HookTask for the inbound request before
+// handing it to provider.TransformRequest, so the raw payload survives even
+// if the downstream Trigger API call later fails. A Store write failure is
+// logged, not fatal - persistence is a safety net, not a prerequisite for
+// serving the webhook.
+func RecordAndTransformRequest(store Store, providerName string, provider hookCommon.Provider, r *http.Request) (hookCommon.TransformResultModel, string) {
+	rawBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return hookCommon.TransformResultModel{Error: err}, ""
+	}
+	r.Body = io.NopCloser(bytes.NewReader(rawBody))
+
+	uuid, err := NewUUID()
+	if err != nil {
+		log.Printf("hookstore: failed to generate UUID, skipping persistence: %s", err)
+		return provider.TransformRequest(r), ""
+	}
+
+	task := HookTask{
+		UUID:           uuid,
+		ReceivedAt:     time.Now(),
+		Provider:       providerName,
+		PayloadVersion: CurrentPayloadVersion,
+		RawHeaders:     r.Header.Clone(),
+		RawBody:        rawBody,
+	}
+	if err := store.Save(task); err != nil {
+		log.Printf("hookstore: failed to save task %s: %s", uuid, err)
+	}
+
+	return provider.TransformRequest(r), uuid
+}
+
+// redeliverResponse is the admin redeliver endpoint's JSON response body.
+type redeliverResponse struct {
+	UUID       string `json:"uuid"`
+	Succeeded  bool   `json:"succeeded"`
+	ShouldSkip bool   `json:"should_skip,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Triggers   int    `json:"trigger_count"`
+}
+
+// TriggerCaller performs a single Bitrise Trigger API call. RedeliverHandler
+// takes one in so redelivery actually re-triggers the build, instead of only
+// re-validating that the stored payload still parses.
+type TriggerCaller func(params bitriseapi.TriggerAPIParamsModel) (bitriseapi.TriggerAPIResponseModel, error)
+
+// RedeliverHandler re-runs a previously stored HookTask through provider's
+// TransformRequest, as if the original request had just arrived, then feeds
+// every resulting TriggerAPIParamsModel to trigger and records the real
+// outcome back onto the stored task. Callers must present adminToken as a
+// Bearer token (see AdminTokenFromEnv) - this endpoint can fire real builds,
+// so it must not be reachable by anyone who can merely guess/enumerate a
+// HookTask UUID.
+func RedeliverHandler(store Store, provider hookCommon.Provider, trigger TriggerCaller, adminToken string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if !isAuthorizedAdminRequest(r, adminToken) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		uuid, ok := parseRedeliverUUID(r.URL.Path)
+		if !ok {
+			http.Error(w, "Invalid redeliver path, expected /admin/hooks/{uuid}/redeliver", http.StatusBadRequest)
+			return
+		}
+
+		task, err := store.Get(uuid)
+		if errors.Is(err, ErrNotFound) {
+			http.Error(w, "Hook task not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Failed to load hook task: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		replayReq, err := rebuildRequest(task)
+		if err != nil {
+			http.Error(w, "Failed to rebuild stored request: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result := provider.TransformRequest(replayReq)
+
+		resp := redeliverResponse{
+			UUID:       uuid,
+			ShouldSkip: result.ShouldSkip,
+			Triggers:   len(result.TriggerAPIParams),
+		}
+
+		switch {
+		case result.Error != nil:
+			resp.Error = result.Error.Error()
+		case result.ShouldSkip:
+			resp.Succeeded = true
+		default:
+			resp.Succeeded = true
+			for _, params := range result.TriggerAPIParams {
+				if _, err := trigger(params); err != nil {
+					resp.Succeeded = false
+					resp.Error = err.Error()
+					break
+				}
+			}
+		}
+
+		respBody, err := json.Marshal(resp)
+		if err != nil {
+			http.Error(w, "Failed to marshal redeliver response: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if err := store.UpdateDelivery(uuid, resp.Succeeded, string(respBody)); err != nil {
+			log.Printf("hookstore: failed to update delivery for task %s: %s", uuid, err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(respBody); err != nil {
+			log.Printf("hookstore: failed to write redeliver response for task %s: %s", uuid, err)
+		}
+	}
+}
+
+// rebuildRequest turns a stored HookTask back into an *http.Request with its
+// PostForm already populated, matching the shape hookCommon.Provider
+// implementations expect from their TransformRequest caller.
+func rebuildRequest(task HookTask) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodPost, "", bytes.NewReader(task.RawBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = task.RawHeaders
+
+	form, err := url.ParseQuery(string(task.RawBody))
+	if err != nil {
+		return nil, err
+	}
+	req.PostForm = form
+
+	return req, nil
+}
+
+func parseRedeliverUUID(path string) (string, bool) {
+	if !strings.HasPrefix(path, redeliverPathPrefix) || !strings.HasSuffix(path, redeliverPathSuffix) {
+		return "", false
+	}
+	uuid := strings.TrimSuffix(strings.TrimPrefix(path, redeliverPathPrefix), redeliverPathSuffix)
+	if uuid == "" {
+		return "", false
+	}
+	return uuid, true
+}
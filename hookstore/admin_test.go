@@ -0,0 +1,229 @@
+package hookstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-webhooks/bitriseapi"
+	hookCommon "github.com/bitrise-io/bitrise-webhooks/service/hook/common"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider is a minimal hookCommon.Provider for exercising
+// RecordAndTransformRequest and RedeliverHandler without depending on a real
+// chat-service package.
+type fakeProvider struct{}
+
+func (fakeProvider) TransformRequest(r *http.Request) hookCommon.TransformResultModel {
+	if r.PostForm.Get("branch") == "" {
+		return hookCommon.TransformResultModel{Error: errMissingBranch}
+	}
+	return hookCommon.TransformResultModel{
+		TriggerAPIParams: []bitriseapi.TriggerAPIParamsModel{
+			{BuildParams: bitriseapi.BuildParamsModel{Branch: r.PostForm.Get("branch")}},
+		},
+	}
+}
+
+func (fakeProvider) TransformResponse(hookCommon.TransformResponseInputModel) hookCommon.TransformResponseModel {
+	return hookCommon.TransformResponseModel{}
+}
+
+func (fakeProvider) TransformErrorMessageResponse(string) hookCommon.TransformResponseModel {
+	return hookCommon.TransformResponseModel{}
+}
+
+func (fakeProvider) TransformSuccessMessageResponse(string) hookCommon.TransformResponseModel {
+	return hookCommon.TransformResponseModel{}
+}
+
+var errMissingBranch = errors.New("Missing branch parameter!")
+
+// fakeTriggerCaller returns a TriggerCaller that records every call it
+// receives and either succeeds or fails with failErr, for exercising
+// RedeliverHandler without a real Bitrise Trigger API client.
+func fakeTriggerCaller(failErr error) (TriggerCaller, *[]bitriseapi.TriggerAPIParamsModel) {
+	var calls []bitriseapi.TriggerAPIParamsModel
+	return func(params bitriseapi.TriggerAPIParamsModel) (bitriseapi.TriggerAPIResponseModel, error) {
+		calls = append(calls, params)
+		if failErr != nil {
+			return bitriseapi.TriggerAPIResponseModel{}, failErr
+		}
+		return bitriseapi.TriggerAPIResponseModel{Status: "ok"}, nil
+	}, &calls
+}
+
+func Test_isAuthorizedAdminRequest(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/admin/hooks/abc-123/redeliver", nil)
+	request.Header.Set("Authorization", "Bearer "+testAdminToken)
+	require.True(t, isAuthorizedAdminRequest(request, testAdminToken))
+
+	wrongToken := httptest.NewRequest(http.MethodPost, "/admin/hooks/abc-123/redeliver", nil)
+	wrongToken.Header.Set("Authorization", "Bearer not-the-token")
+	require.False(t, isAuthorizedAdminRequest(wrongToken, testAdminToken))
+
+	noHeader := httptest.NewRequest(http.MethodPost, "/admin/hooks/abc-123/redeliver", nil)
+	require.False(t, isAuthorizedAdminRequest(noHeader, testAdminToken))
+
+	notBearer := httptest.NewRequest(http.MethodPost, "/admin/hooks/abc-123/redeliver", nil)
+	notBearer.Header.Set("Authorization", testAdminToken)
+	require.False(t, isAuthorizedAdminRequest(notBearer, testAdminToken))
+
+	unconfigured := httptest.NewRequest(http.MethodPost, "/admin/hooks/abc-123/redeliver", nil)
+	unconfigured.Header.Set("Authorization", "Bearer "+testAdminToken)
+	require.False(t, isAuthorizedAdminRequest(unconfigured, ""))
+}
+
+func Test_parseRedeliverUUID(t *testing.T) {
+	uuid, ok := parseRedeliverUUID("/admin/hooks/abc-123/redeliver")
+	require.True(t, ok)
+	require.Equal(t, "abc-123", uuid)
+
+	_, ok = parseRedeliverUUID("/admin/hooks//redeliver")
+	require.False(t, ok)
+
+	_, ok = parseRedeliverUUID("/admin/hooks/abc-123")
+	require.False(t, ok)
+}
+
+func Test_RecordAndTransformRequest(t *testing.T) {
+	store := newFakeStore()
+
+	body := "branch=master"
+	request := httptest.NewRequest(http.MethodPost, "/h/slack", bytes.NewReader([]byte(body)))
+	request.PostForm = url.Values{"branch": {"master"}}
+
+	result, uuid := RecordAndTransformRequest(store, "slack", fakeProvider{}, request)
+	require.NoError(t, result.Error)
+	require.NotEmpty(t, uuid)
+
+	task, err := store.Get(uuid)
+	require.NoError(t, err)
+	require.Equal(t, "slack", task.Provider)
+	require.Equal(t, CurrentPayloadVersion, task.PayloadVersion)
+	require.Equal(t, []byte(body), task.RawBody)
+}
+
+const testAdminToken = "test-admin-token"
+
+func Test_RedeliverHandler(t *testing.T) {
+	t.Log("Missing Authorization header - 401, nothing replayed")
+	{
+		store := newFakeStore()
+		trigger, calls := fakeTriggerCaller(nil)
+		handler := RedeliverHandler(store, fakeProvider{}, trigger, testAdminToken)
+
+		require.NoError(t, store.Save(HookTask{UUID: "abc-123", RawBody: []byte("branch=master")}))
+
+		request := httptest.NewRequest(http.MethodPost, "/admin/hooks/abc-123/redeliver", nil)
+		recorder := httptest.NewRecorder()
+		handler(recorder, request)
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+		require.Empty(t, *calls)
+	}
+
+	t.Log("Wrong token - 401")
+	{
+		store := newFakeStore()
+		trigger, _ := fakeTriggerCaller(nil)
+		handler := RedeliverHandler(store, fakeProvider{}, trigger, testAdminToken)
+
+		request := httptest.NewRequest(http.MethodPost, "/admin/hooks/abc-123/redeliver", nil)
+		request.Header.Set("Authorization", "Bearer wrong-token")
+		recorder := httptest.NewRecorder()
+		handler(recorder, request)
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	}
+
+	t.Log("No admin token configured - always 401, even with a correct-looking header")
+	{
+		store := newFakeStore()
+		trigger, _ := fakeTriggerCaller(nil)
+		handler := RedeliverHandler(store, fakeProvider{}, trigger, "")
+
+		request := httptest.NewRequest(http.MethodPost, "/admin/hooks/abc-123/redeliver", nil)
+		request.Header.Set("Authorization", "Bearer ")
+		recorder := httptest.NewRecorder()
+		handler(recorder, request)
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	}
+
+	t.Log("Unknown uuid - 404")
+	{
+		store := newFakeStore()
+		trigger, _ := fakeTriggerCaller(nil)
+		handler := RedeliverHandler(store, fakeProvider{}, trigger, testAdminToken)
+
+		request := httptest.NewRequest(http.MethodPost, "/admin/hooks/missing/redeliver", nil)
+		request.Header.Set("Authorization", "Bearer "+testAdminToken)
+		recorder := httptest.NewRecorder()
+		handler(recorder, request)
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+	}
+
+	t.Log("Known uuid - re-runs through the provider and re-triggers the build")
+	{
+		store := newFakeStore()
+		trigger, calls := fakeTriggerCaller(nil)
+		handler := RedeliverHandler(store, fakeProvider{}, trigger, testAdminToken)
+
+		require.NoError(t, store.Save(HookTask{
+			UUID:       "abc-123",
+			RawHeaders: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+			RawBody:    []byte("branch=master"),
+		}))
+
+		request := httptest.NewRequest(http.MethodPost, "/admin/hooks/abc-123/redeliver", nil)
+		request.Header.Set("Authorization", "Bearer "+testAdminToken)
+		recorder := httptest.NewRecorder()
+		handler(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var resp redeliverResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		require.True(t, resp.Succeeded)
+		require.Equal(t, 1, resp.Triggers)
+		require.Len(t, *calls, 1)
+		require.Equal(t, "master", (*calls)[0].BuildParams.Branch)
+
+		task, err := store.Get("abc-123")
+		require.NoError(t, err)
+		require.True(t, task.Delivered)
+		require.True(t, task.Succeeded)
+		require.NotEmpty(t, task.ResponseBody)
+	}
+
+	t.Log("Known uuid - trigger call fails, outcome recorded as not succeeded")
+	{
+		store := newFakeStore()
+		trigger, _ := fakeTriggerCaller(errors.New("Trigger API unavailable"))
+		handler := RedeliverHandler(store, fakeProvider{}, trigger, testAdminToken)
+
+		require.NoError(t, store.Save(HookTask{
+			UUID:       "def-456",
+			RawHeaders: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+			RawBody:    []byte("branch=master"),
+		}))
+
+		request := httptest.NewRequest(http.MethodPost, "/admin/hooks/def-456/redeliver", nil)
+		request.Header.Set("Authorization", "Bearer "+testAdminToken)
+		recorder := httptest.NewRecorder()
+		handler(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var resp redeliverResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		require.False(t, resp.Succeeded)
+		require.Equal(t, "Trigger API unavailable", resp.Error)
+
+		task, err := store.Get("def-456")
+		require.NoError(t, err)
+		require.True(t, task.Delivered)
+		require.False(t, task.Succeeded)
+	}
+}
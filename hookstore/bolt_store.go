@@ -0,0 +1,146 @@
+package hookstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// hookTasksBucket is the single BoltDB bucket HookTasks are stored in, keyed
+// by UUID.
+var hookTasksBucket = []byte("hook_tasks")
+
+// boltHookTask is the JSON-on-disk shape of a HookTask. HookTask itself
+// isn't marshalled directly so the on-disk format can evolve independently
+// of the in-memory struct - see CurrentPayloadVersion.
+type boltHookTask struct {
+	UUID           string      `json:"uuid"`
+	ReceivedAt     time.Time   `json:"received_at"`
+	Provider       string      `json:"provider"`
+	PayloadVersion int         `json:"payload_version"`
+	RawHeaders     http.Header `json:"raw_headers"`
+	RawBody        []byte      `json:"raw_body"`
+	Delivered      bool        `json:"delivered"`
+	Succeeded      bool        `json:"succeeded"`
+	ResponseBody   string      `json:"response_body"`
+}
+
+func toBoltHookTask(task HookTask) boltHookTask {
+	return boltHookTask(task)
+}
+
+func (t boltHookTask) toHookTask() HookTask {
+	return HookTask(t)
+}
+
+type boltStore struct {
+	db *bolt.DB
+}
+
+func newBoltStore(path string) (*boltStore, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("hookstore: failed to open bolt db: %s", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(hookTasksBucket)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("hookstore: failed to create bucket: %s", err)
+	}
+
+	return &boltStore{db: db}, nil
+}
+
+func (s *boltStore) Save(task HookTask) error {
+	data, err := json.Marshal(toBoltHookTask(task))
+	if err != nil {
+		return fmt.Errorf("hookstore: failed to marshal task: %s", err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(hookTasksBucket).Put([]byte(task.UUID), data)
+	})
+}
+
+func (s *boltStore) Get(uuid string) (HookTask, error) {
+	var task HookTask
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(hookTasksBucket).Get([]byte(uuid))
+		if data == nil {
+			return ErrNotFound
+		}
+
+		var stored boltHookTask
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return fmt.Errorf("hookstore: failed to unmarshal task: %s", err)
+		}
+		task = stored.toHookTask()
+		return nil
+	})
+	return task, err
+}
+
+func (s *boltStore) UpdateDelivery(uuid string, succeeded bool, responseBody string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(hookTasksBucket)
+		data := bucket.Get([]byte(uuid))
+		if data == nil {
+			return ErrNotFound
+		}
+
+		var stored boltHookTask
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return fmt.Errorf("hookstore: failed to unmarshal task: %s", err)
+		}
+		stored.Delivered = true
+		stored.Succeeded = succeeded
+		stored.ResponseBody = responseBody
+
+		updated, err := json.Marshal(stored)
+		if err != nil {
+			return fmt.Errorf("hookstore: failed to marshal task: %s", err)
+		}
+		return bucket.Put([]byte(uuid), updated)
+	})
+}
+
+func (s *boltStore) DeleteReceivedBefore(cutoff time.Time) (int, error) {
+	trimmed := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(hookTasksBucket)
+		var staleKeys [][]byte
+
+		err := bucket.ForEach(func(key, data []byte) error {
+			var stored boltHookTask
+			if err := json.Unmarshal(data, &stored); err != nil {
+				return fmt.Errorf("hookstore: failed to unmarshal task: %s", err)
+			}
+			if stored.ReceivedAt.Before(cutoff) {
+				staleKeys = append(staleKeys, append([]byte(nil), key...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range staleKeys {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+			trimmed++
+		}
+		return nil
+	})
+	return trimmed, err
+}
+
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,78 @@
+package hookstore
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_boltStore_SaveGetUpdateDeliveryDeleteReceivedBefore(t *testing.T) {
+	store, err := newBoltStore(filepath.Join(t.TempDir(), "hookstore.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	receivedAt := time.Now().Add(-1 * time.Hour).Truncate(time.Second).UTC()
+	task := HookTask{
+		UUID:           "abc-123",
+		ReceivedAt:     receivedAt,
+		Provider:       "slack",
+		PayloadVersion: CurrentPayloadVersion,
+		RawHeaders:     http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+		RawBody:        []byte("trigger_word=bitrise%3A&text=bitrise%3A+branch%3Amaster"),
+	}
+	require.NoError(t, store.Save(task))
+
+	t.Log("Get round-trips the saved task")
+	{
+		got, err := store.Get("abc-123")
+		require.NoError(t, err)
+		require.Equal(t, task.UUID, got.UUID)
+		require.True(t, receivedAt.Equal(got.ReceivedAt))
+		require.Equal(t, task.Provider, got.Provider)
+		require.Equal(t, task.PayloadVersion, got.PayloadVersion)
+		require.Equal(t, task.RawHeaders, got.RawHeaders)
+		require.Equal(t, task.RawBody, got.RawBody)
+		require.False(t, got.Delivered)
+		require.False(t, got.Succeeded)
+	}
+
+	t.Log("Get - unknown uuid")
+	{
+		_, err := store.Get("missing")
+		require.Equal(t, ErrNotFound, err)
+	}
+
+	t.Log("UpdateDelivery records the outcome")
+	{
+		require.NoError(t, store.UpdateDelivery("abc-123", true, `{"uuid":"abc-123"}`))
+
+		got, err := store.Get("abc-123")
+		require.NoError(t, err)
+		require.True(t, got.Delivered)
+		require.True(t, got.Succeeded)
+		require.Equal(t, `{"uuid":"abc-123"}`, got.ResponseBody)
+	}
+
+	t.Log("UpdateDelivery - unknown uuid")
+	{
+		require.Equal(t, ErrNotFound, store.UpdateDelivery("missing", true, ""))
+	}
+
+	t.Log("DeleteReceivedBefore only removes stale tasks")
+	{
+		require.NoError(t, store.Save(HookTask{UUID: "fresh", ReceivedAt: time.Now()}))
+
+		trimmed, err := store.DeleteReceivedBefore(time.Now().Add(-1 * time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, 1, trimmed)
+
+		_, err = store.Get("abc-123")
+		require.Equal(t, ErrNotFound, err)
+
+		_, err = store.Get("fresh")
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,53 @@
+package hookstore
+
+import (
+	"fmt"
+	"os"
+)
+
+// driverEnvKey selects which Store backend NewStoreFromEnv constructs.
+const driverEnvKey = "HOOKSTORE_DRIVER"
+
+// pathEnvKey is the on-disk path for the sqlite/bolt drivers. Ignored by the
+// none driver.
+const pathEnvKey = "HOOKSTORE_PATH"
+
+const (
+	// DriverNone disables persistence entirely: Save/UpdateDelivery are
+	// no-ops and Get always returns ErrNotFound.
+	DriverNone = "none"
+	// DriverSQLite stores HookTasks in a SQLite database file.
+	DriverSQLite = "sqlite"
+	// DriverBolt stores HookTasks in a BoltDB file.
+	DriverBolt = "bolt"
+)
+
+// defaultStorePath is used when HOOKSTORE_PATH isn't set.
+const defaultStorePath = "hookstore.db"
+
+// NewStoreFromEnv builds a Store based on the HOOKSTORE_DRIVER and
+// HOOKSTORE_PATH env vars. An unset or empty HOOKSTORE_DRIVER behaves like
+// DriverNone, so persistence is opt-in.
+func NewStoreFromEnv() (Store, error) {
+	driver := os.Getenv(driverEnvKey)
+	path := os.Getenv(pathEnvKey)
+	if path == "" {
+		path = defaultStorePath
+	}
+	return NewStore(driver, path)
+}
+
+// NewStore builds a Store for the given driver name and, for the sqlite/bolt
+// drivers, on-disk path.
+func NewStore(driver, path string) (Store, error) {
+	switch driver {
+	case "", DriverNone:
+		return newNopStore(), nil
+	case DriverSQLite:
+		return newSQLiteStore(path)
+	case DriverBolt:
+		return newBoltStore(path)
+	default:
+		return nil, fmt.Errorf("hookstore: unknown %s %q", driverEnvKey, driver)
+	}
+}
@@ -0,0 +1,68 @@
+// Package hookstore persists the raw bytes of inbound webhook requests so
+// that a failed downstream Bitrise Trigger API call doesn't lose the
+// original payload: the stored HookTask can be inspected, or replayed
+// through the provider pipeline via the admin redeliver endpoint.
+package hookstore
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// CurrentPayloadVersion is stamped onto every HookTask this package writes.
+// It's bumped whenever the stored shape changes, so a future reader can tell
+// which on-disk format (if any) a row needs migrating from.
+const CurrentPayloadVersion = 2
+
+// ErrNotFound is returned by Store.Get when no HookTask exists for a UUID.
+var ErrNotFound = errors.New("hookstore: task not found")
+
+// HookTask is a single inbound webhook request, captured before it's handed
+// to a hookCommon.Provider, plus the outcome of delivering its trigger
+// result.
+type HookTask struct {
+	UUID           string
+	ReceivedAt     time.Time
+	Provider       string
+	PayloadVersion int
+	RawHeaders     http.Header
+	RawBody        []byte
+
+	Delivered    bool
+	Succeeded    bool
+	ResponseBody string
+}
+
+// Store persists HookTasks. Implementations are selected at runtime via
+// NewStoreFromEnv, based on the HOOKSTORE_DRIVER env var.
+type Store interface {
+	// Save writes a newly received HookTask.
+	Save(task HookTask) error
+	// Get looks up a HookTask by UUID. Returns ErrNotFound if it doesn't exist.
+	Get(uuid string) (HookTask, error)
+	// UpdateDelivery records the outcome of delivering a HookTask's trigger
+	// result.
+	UpdateDelivery(uuid string, succeeded bool, responseBody string) error
+	// DeleteReceivedBefore removes every HookTask received before cutoff,
+	// returning the number of rows removed. Used by Reaper.
+	DeleteReceivedBefore(cutoff time.Time) (int, error)
+	// Close releases any resources (file handles, connections) held by the
+	// Store.
+	Close() error
+}
+
+// NewUUID generates a random (v4-ish) UUID string for a new HookTask. It
+// doesn't claim RFC 4122 compliance beyond version/variant bits - it only
+// needs to be a usable, collision-resistant identifier.
+func NewUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate UUID: %s", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
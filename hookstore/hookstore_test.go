@@ -0,0 +1,59 @@
+package hookstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewUUID(t *testing.T) {
+	first, err := NewUUID()
+	require.NoError(t, err)
+	require.Len(t, first, 36)
+
+	second, err := NewUUID()
+	require.NoError(t, err)
+	require.NotEqual(t, first, second)
+}
+
+func Test_NewStore(t *testing.T) {
+	t.Log("Empty driver behaves like none")
+	{
+		store, err := NewStore("", "unused")
+		require.NoError(t, err)
+		_, ok := store.(*nopStore)
+		require.True(t, ok)
+	}
+
+	t.Log("none")
+	{
+		store, err := NewStore(DriverNone, "unused")
+		require.NoError(t, err)
+		_, ok := store.(*nopStore)
+		require.True(t, ok)
+	}
+
+	t.Log("unknown driver")
+	{
+		_, err := NewStore("postgres", "unused")
+		require.EqualError(t, err, `hookstore: unknown HOOKSTORE_DRIVER "postgres"`)
+	}
+}
+
+func Test_nopStore(t *testing.T) {
+	store := newNopStore()
+
+	require.NoError(t, store.Save(HookTask{UUID: "a"}))
+
+	_, err := store.Get("a")
+	require.Equal(t, ErrNotFound, err)
+
+	require.NoError(t, store.UpdateDelivery("a", true, "body"))
+
+	trimmed, err := store.DeleteReceivedBefore(time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, 0, trimmed)
+
+	require.NoError(t, store.Close())
+}
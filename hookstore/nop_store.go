@@ -0,0 +1,32 @@
+package hookstore
+
+import "time"
+
+// nopStore is the DriverNone Store: it discards everything it's given. It
+// lets callers unconditionally go through the Store interface without
+// branching on whether persistence is actually configured.
+type nopStore struct{}
+
+func newNopStore() *nopStore {
+	return &nopStore{}
+}
+
+func (s *nopStore) Save(task HookTask) error {
+	return nil
+}
+
+func (s *nopStore) Get(uuid string) (HookTask, error) {
+	return HookTask{}, ErrNotFound
+}
+
+func (s *nopStore) UpdateDelivery(uuid string, succeeded bool, responseBody string) error {
+	return nil
+}
+
+func (s *nopStore) DeleteReceivedBefore(cutoff time.Time) (int, error) {
+	return 0, nil
+}
+
+func (s *nopStore) Close() error {
+	return nil
+}
@@ -0,0 +1,66 @@
+package hookstore
+
+import (
+	"log"
+	"time"
+)
+
+// defaultReapInterval is how often the Reaper checks for stale HookTasks,
+// used when NewReaper is called with interval <= 0.
+const defaultReapInterval = 1 * time.Hour
+
+// Reaper periodically trims HookTasks older than TTL from a Store, so
+// persisted payloads don't accumulate forever.
+type Reaper struct {
+	store    Store
+	ttl      time.Duration
+	interval time.Duration
+	stop     chan struct{}
+}
+
+// NewReaper builds a Reaper that removes HookTasks older than ttl, checking
+// every interval. A non-positive interval falls back to defaultReapInterval.
+func NewReaper(store Store, ttl, interval time.Duration) *Reaper {
+	if interval <= 0 {
+		interval = defaultReapInterval
+	}
+	return &Reaper{
+		store:    store,
+		ttl:      ttl,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start runs the reap loop in the background until Stop is called.
+func (r *Reaper) Start() {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.reapOnce()
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the reap loop. It doesn't wait for an in-progress reap to finish.
+func (r *Reaper) Stop() {
+	close(r.stop)
+}
+
+func (r *Reaper) reapOnce() {
+	trimmed, err := r.store.DeleteReceivedBefore(time.Now().Add(-r.ttl))
+	if err != nil {
+		log.Printf("hookstore: reaper failed to trim old tasks: %s", err)
+		return
+	}
+	if trimmed > 0 {
+		log.Printf("hookstore: reaper trimmed %d task(s) older than %s", trimmed, r.ttl)
+	}
+}
@@ -0,0 +1,28 @@
+package hookstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Reaper_reapOnce(t *testing.T) {
+	store := newFakeStore()
+	require.NoError(t, store.Save(HookTask{UUID: "stale", ReceivedAt: time.Now().Add(-2 * time.Hour)}))
+	require.NoError(t, store.Save(HookTask{UUID: "fresh", ReceivedAt: time.Now()}))
+
+	reaper := NewReaper(store, 1*time.Hour, 0)
+	reaper.reapOnce()
+
+	_, err := store.Get("stale")
+	require.Equal(t, ErrNotFound, err)
+
+	_, err = store.Get("fresh")
+	require.NoError(t, err)
+}
+
+func Test_NewReaper_defaultInterval(t *testing.T) {
+	reaper := NewReaper(newFakeStore(), time.Hour, 0)
+	require.Equal(t, defaultReapInterval, reaper.interval)
+}
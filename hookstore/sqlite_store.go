@@ -0,0 +1,114 @@
+package hookstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	// Registers the "sqlite3" database/sql driver.
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS hook_tasks (
+	uuid            TEXT PRIMARY KEY,
+	received_at     INTEGER NOT NULL,
+	provider        TEXT NOT NULL,
+	payload_version INTEGER NOT NULL,
+	raw_headers     TEXT NOT NULL,
+	raw_body        BLOB NOT NULL,
+	delivered       INTEGER NOT NULL DEFAULT 0,
+	succeeded       INTEGER NOT NULL DEFAULT 0,
+	response_body   TEXT NOT NULL DEFAULT ''
+);`
+
+type sqliteStore struct {
+	db *sql.DB
+}
+
+func newSQLiteStore(path string) (*sqliteStore, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("hookstore: failed to open sqlite db: %s", err)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return nil, fmt.Errorf("hookstore: failed to create schema: %s", err)
+	}
+	return &sqliteStore{db: db}, nil
+}
+
+func (s *sqliteStore) Save(task HookTask) error {
+	rawHeaders, err := json.Marshal(task.RawHeaders)
+	if err != nil {
+		return fmt.Errorf("hookstore: failed to marshal headers: %s", err)
+	}
+
+	_, err = s.db.Exec(
+		`INSERT INTO hook_tasks (uuid, received_at, provider, payload_version, raw_headers, raw_body, delivered, succeeded, response_body)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		task.UUID, task.ReceivedAt.Unix(), task.Provider, task.PayloadVersion, rawHeaders, task.RawBody,
+		task.Delivered, task.Succeeded, task.ResponseBody,
+	)
+	if err != nil {
+		return fmt.Errorf("hookstore: failed to save task: %s", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) Get(uuid string) (HookTask, error) {
+	row := s.db.QueryRow(
+		`SELECT uuid, received_at, provider, payload_version, raw_headers, raw_body, delivered, succeeded, response_body
+		 FROM hook_tasks WHERE uuid = ?`, uuid,
+	)
+
+	var (
+		task       HookTask
+		receivedAt int64
+		rawHeaders []byte
+	)
+	err := row.Scan(&task.UUID, &receivedAt, &task.Provider, &task.PayloadVersion, &rawHeaders, &task.RawBody,
+		&task.Delivered, &task.Succeeded, &task.ResponseBody)
+	if err == sql.ErrNoRows {
+		return HookTask{}, ErrNotFound
+	}
+	if err != nil {
+		return HookTask{}, fmt.Errorf("hookstore: failed to load task: %s", err)
+	}
+
+	task.ReceivedAt = time.Unix(receivedAt, 0).UTC()
+	if err := json.Unmarshal(rawHeaders, &task.RawHeaders); err != nil {
+		return HookTask{}, fmt.Errorf("hookstore: failed to unmarshal headers: %s", err)
+	}
+	return task, nil
+}
+
+func (s *sqliteStore) UpdateDelivery(uuid string, succeeded bool, responseBody string) error {
+	res, err := s.db.Exec(
+		`UPDATE hook_tasks SET delivered = 1, succeeded = ?, response_body = ? WHERE uuid = ?`,
+		succeeded, responseBody, uuid,
+	)
+	if err != nil {
+		return fmt.Errorf("hookstore: failed to update task: %s", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *sqliteStore) DeleteReceivedBefore(cutoff time.Time) (int, error) {
+	res, err := s.db.Exec(`DELETE FROM hook_tasks WHERE received_at < ?`, cutoff.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("hookstore: failed to trim old tasks: %s", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("hookstore: failed to count trimmed tasks: %s", err)
+	}
+	return int(n), nil
+}
+
+func (s *sqliteStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,65 @@
+package hookstore
+
+import (
+	"sync"
+	"time"
+)
+
+// fakeStore is an in-memory Store used by tests that need real persistence
+// semantics (unlike nopStore) without depending on sqlite/bolt.
+type fakeStore struct {
+	mu    sync.Mutex
+	tasks map[string]HookTask
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{tasks: map[string]HookTask{}}
+}
+
+func (s *fakeStore) Save(task HookTask) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks[task.UUID] = task
+	return nil
+}
+
+func (s *fakeStore) Get(uuid string) (HookTask, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.tasks[uuid]
+	if !ok {
+		return HookTask{}, ErrNotFound
+	}
+	return task, nil
+}
+
+func (s *fakeStore) UpdateDelivery(uuid string, succeeded bool, responseBody string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.tasks[uuid]
+	if !ok {
+		return ErrNotFound
+	}
+	task.Delivered = true
+	task.Succeeded = succeeded
+	task.ResponseBody = responseBody
+	s.tasks[uuid] = task
+	return nil
+}
+
+func (s *fakeStore) DeleteReceivedBefore(cutoff time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	trimmed := 0
+	for uuid, task := range s.tasks {
+		if task.ReceivedAt.Before(cutoff) {
+			delete(s.tasks, uuid)
+			trimmed++
+		}
+	}
+	return trimmed, nil
+}
+
+func (s *fakeStore) Close() error {
+	return nil
+}
@@ -0,0 +1,77 @@
+package hookCommon
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bitrise-io/bitrise-webhooks/bitriseapi"
+)
+
+// TransformResultModel is the result of transforming an inbound webhook
+// request into one or more Bitrise Trigger API calls.
+type TransformResultModel struct {
+	TriggerAPIParams []bitriseapi.TriggerAPIParamsModel
+	ShouldSkip       bool
+	Error            error
+	// ResponseURL is set by providers whose inbound request carries its own
+	// out-of-band response address (e.g. a Slack slash command's
+	// `response_url`), so TransformResponse can be told about it via
+	// TransformResponseInputModel without keeping it as provider state.
+	ResponseURL string
+}
+
+// TransformResponseInputModel is the input for building the hook provider's
+// response, based on the results of the performed Trigger API calls.
+type TransformResponseInputModel struct {
+	SuccessTriggerResponses []bitriseapi.TriggerAPIResponseModel
+	FailedTriggerResponses  []bitriseapi.TriggerAPIResponseModel
+	Errors                  []string
+	// ResponseURL carries over TransformResultModel.ResponseURL, for
+	// providers that need to deliver the response out-of-band instead of in
+	// the sync HTTP response.
+	ResponseURL string
+}
+
+// TransformResponseModel is the response a hook provider wants to send back
+// to the caller of the webhook.
+type TransformResponseModel struct {
+	Data           interface{}
+	ContentType    string
+	HTTPStatusCode int
+}
+
+// Provider is the interface every webhook/service hook provider implements.
+type Provider interface {
+	TransformRequest(r *http.Request) TransformResultModel
+	TransformResponse(input TransformResponseInputModel) TransformResponseModel
+	TransformErrorMessageResponse(errMsg string) TransformResponseModel
+	TransformSuccessMessageResponse(msg string) TransformResponseModel
+}
+
+// authenticationError marks a TransformResultModel.Error as a failed
+// request-authentication check (bad signature, bad legacy token, ...), as
+// opposed to a generic parse/validation failure. The HTTP layer can use
+// IsAuthenticationError to map it to 401 instead of whatever status code it
+// uses for other TransformRequest errors.
+type authenticationError struct {
+	msg string
+}
+
+// NewAuthenticationError wraps msg as an authentication failure: a
+// TransformRequest implementation should return it whenever it rejects a
+// request for failing a signature/token check, so the caller can tell that
+// apart from a malformed or unsupported payload.
+func NewAuthenticationError(msg string) error {
+	return &authenticationError{msg: msg}
+}
+
+func (e *authenticationError) Error() string {
+	return e.msg
+}
+
+// IsAuthenticationError reports whether err (or any error it wraps) was
+// created with NewAuthenticationError.
+func IsAuthenticationError(err error) bool {
+	var authErr *authenticationError
+	return errors.As(err, &authErr)
+}
@@ -0,0 +1,29 @@
+package hookCommon
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IsAuthenticationError(t *testing.T) {
+	t.Log("Authentication error")
+	{
+		err := NewAuthenticationError("Slack signature verification failed")
+		require.EqualError(t, err, "Slack signature verification failed")
+		require.True(t, IsAuthenticationError(err))
+	}
+
+	t.Log("Wrapped authentication error")
+	{
+		err := fmt.Errorf("Failed to parse the request/message: %w", NewAuthenticationError("bad token"))
+		require.True(t, IsAuthenticationError(err))
+	}
+
+	t.Log("Unrelated error")
+	{
+		require.False(t, IsAuthenticationError(errors.New("Missing required parameter: 'text'")))
+	}
+}
@@ -0,0 +1,51 @@
+package hookCommon
+
+import (
+	"net/http"
+
+	"github.com/bitrise-io/bitrise-webhooks/bitriseapi"
+)
+
+// PayloadConvertor turns Trigger API results into the payload shape a
+// specific chat service expects (plain text, embeds, cards, blocks, ...).
+// Every hook provider that only differs from Slack in *how* it renders the
+// trigger results - not in how it parses the inbound request - implements
+// one of these instead of duplicating TransformResponse from scratch.
+type PayloadConvertor interface {
+	// Success renders a single successful trigger response.
+	Success(resp bitriseapi.TriggerAPIResponseModel) interface{}
+	// Failure renders a single failed trigger response.
+	Failure(resp bitriseapi.TriggerAPIResponseModel) interface{}
+	// Error renders a single error message.
+	Error(errMsg string) interface{}
+	// Wrap assembles the rendered successes/failures/errors into the final
+	// request body, alongside the Content-Type it should be sent with.
+	Wrap(successes, faileds, errs []interface{}) (body interface{}, contentType string)
+}
+
+// BuildTransformResponse renders a TransformResponseInputModel with the given
+// PayloadConvertor, so providers only have to implement the rendering, not
+// the aggregation.
+func BuildTransformResponse(convertor PayloadConvertor, input TransformResponseInputModel) TransformResponseModel {
+	successes := make([]interface{}, 0, len(input.SuccessTriggerResponses))
+	for _, aResp := range input.SuccessTriggerResponses {
+		successes = append(successes, convertor.Success(aResp))
+	}
+
+	faileds := make([]interface{}, 0, len(input.FailedTriggerResponses))
+	for _, aResp := range input.FailedTriggerResponses {
+		faileds = append(faileds, convertor.Failure(aResp))
+	}
+
+	errs := make([]interface{}, 0, len(input.Errors))
+	for _, anErr := range input.Errors {
+		errs = append(errs, convertor.Error(anErr))
+	}
+
+	body, contentType := convertor.Wrap(successes, faileds, errs)
+	return TransformResponseModel{
+		Data:           body,
+		ContentType:    contentType,
+		HTTPStatusCode: http.StatusOK,
+	}
+}
@@ -0,0 +1,134 @@
+// Package textparams implements the pipe-separated `key: value` trigger
+// parameter syntax (e.g. "branch: master | tag: v1.0") shared by every hook
+// provider whose trigger mechanism is a plain chat message/command, rather
+// than a structured event payload.
+package textparams
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-webhooks/bitriseapi"
+	hookCommon "github.com/bitrise-io/bitrise-webhooks/service/hook/common"
+)
+
+// pipeSeparatedItem is a single `key: value` component of a pipe-separated
+// parameter text, in the order it was declared.
+type pipeSeparatedItem struct {
+	key   string
+	value string
+}
+
+func splitPipeSeparatedItems(text string) []pipeSeparatedItem {
+	var items []pipeSeparatedItem
+	for _, aItem := range strings.Split(text, "|") {
+		aItem = strings.TrimSpace(aItem)
+		if aItem == "" {
+			continue
+		}
+
+		colonIdx := strings.Index(aItem, ":")
+		if colonIdx < 0 {
+			continue
+		}
+
+		key := strings.TrimSpace(aItem[:colonIdx])
+		value := strings.TrimSpace(aItem[colonIdx+1:])
+		if key == "" {
+			continue
+		}
+		items = append(items, pipeSeparatedItem{key: key, value: value})
+	}
+	return items
+}
+
+// CollectParams splits a pipe-separated parameter text into a key->value map.
+// Repeated keys overwrite earlier ones, `env` included; only Transform
+// accumulates repeated `env` entries.
+func CollectParams(text string) map[string]string {
+	collectedParams := map[string]string{}
+	for _, item := range splitPipeSeparatedItems(text) {
+		collectedParams[item.key] = item.value
+	}
+	return collectedParams
+}
+
+// knownParamKeys are the `key:` components understood by Transform. Anything
+// else is rejected, so a typo'd key doesn't silently drop a parameter.
+var knownParamKeys = map[string]bool{
+	"branch":   true,
+	"tag":      true,
+	"commit":   true,
+	"message":  true,
+	"workflow": true,
+	"env":      true,
+}
+
+// parseEnvironmentItemText parses an `env:` value in `KEY=VALUE` form.
+func parseEnvironmentItemText(envText string) (string, string, error) {
+	eqIdx := strings.Index(envText, "=")
+	if eqIdx < 0 {
+		return "", "", fmt.Errorf("Invalid 'env' parameter, expected format: KEY=VALUE, got: %s", envText)
+	}
+
+	key := strings.TrimSpace(envText[:eqIdx])
+	if key == "" {
+		return "", "", fmt.Errorf("Invalid 'env' parameter, missing key, got: %s", envText)
+	}
+
+	return key, envText[eqIdx+1:], nil
+}
+
+// Transform parses `text` (with the leading `triggerText` trigger stripped)
+// as pipe-separated trigger parameters and turns it into Trigger API params.
+func Transform(triggerText, text string) hookCommon.TransformResultModel {
+	paramsText := strings.TrimPrefix(text, triggerText)
+
+	params := map[string]string{}
+	var environments []bitriseapi.EnvironmentItem
+	for _, item := range splitPipeSeparatedItems(paramsText) {
+		if !knownParamKeys[item.key] {
+			return hookCommon.TransformResultModel{
+				Error: fmt.Errorf("Unknown parameter: '%s'", item.key),
+			}
+		}
+
+		if item.key == "env" {
+			envKey, envValue, err := parseEnvironmentItemText(item.value)
+			if err != nil {
+				return hookCommon.TransformResultModel{Error: err}
+			}
+			environments = append(environments, bitriseapi.EnvironmentItem{
+				MappedTo: envKey,
+				Value:    envValue,
+				IsExpand: true,
+			})
+			continue
+		}
+
+		params[item.key] = item.value
+	}
+
+	branch := params["branch"]
+	if branch == "" {
+		return hookCommon.TransformResultModel{
+			Error: errors.New("Missing branch parameter!"),
+		}
+	}
+
+	buildParams := bitriseapi.BuildParamsModel{
+		Branch:        branch,
+		Tag:           params["tag"],
+		CommitHash:    params["commit"],
+		CommitMessage: params["message"],
+		WorkflowID:    params["workflow"],
+		Environments:  environments,
+	}
+
+	return hookCommon.TransformResultModel{
+		TriggerAPIParams: []bitriseapi.TriggerAPIParamsModel{
+			{BuildParams: buildParams},
+		},
+	}
+}
@@ -0,0 +1,59 @@
+package textparams
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/bitrise-webhooks/bitriseapi"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CollectParams(t *testing.T) {
+	collectedParams := CollectParams("key1: value 1 |   key2 : value 2")
+	require.Equal(t, map[string]string{
+		"key1": "value 1",
+		"key2": "value 2",
+	}, collectedParams)
+}
+
+func Test_Transform(t *testing.T) {
+	t.Log("Should be OK")
+	{
+		result := Transform("bitrise:", "bitrise: branch:master")
+		require.NoError(t, result.Error)
+		require.Equal(t, []bitriseapi.TriggerAPIParamsModel{
+			{BuildParams: bitriseapi.BuildParamsModel{Branch: "master"}},
+		}, result.TriggerAPIParams)
+	}
+
+	t.Log("Workflow and repeated env parameters")
+	{
+		result := Transform("bitrise -", "bitrise - env: DEPLOY_TARGET=staging | env: BUILD_NUMBER=42 | branch: main | workflow: deploy")
+		require.NoError(t, result.Error)
+		require.Equal(t, []bitriseapi.TriggerAPIParamsModel{
+			{
+				BuildParams: bitriseapi.BuildParamsModel{
+					Branch:     "main",
+					WorkflowID: "deploy",
+					Environments: []bitriseapi.EnvironmentItem{
+						{MappedTo: "DEPLOY_TARGET", Value: "staging", IsExpand: true},
+						{MappedTo: "BUILD_NUMBER", Value: "42", IsExpand: true},
+					},
+				},
+			},
+		}, result.TriggerAPIParams)
+	}
+
+	t.Log("Unknown key is rejected")
+	{
+		result := Transform("bitrise -", "bitrise - branch: main | bracnh: typo")
+		require.EqualError(t, result.Error, "Unknown parameter: 'bracnh'")
+		require.Nil(t, result.TriggerAPIParams)
+	}
+
+	t.Log("Missing branch parameter")
+	{
+		result := Transform("bitrise:", "bitrise: no branch")
+		require.EqualError(t, result.Error, "Missing branch parameter!")
+		require.Nil(t, result.TriggerAPIParams)
+	}
+}
@@ -0,0 +1,60 @@
+// Package webhookform holds the form-urlencoded request parsing that's
+// identical across the outgoing-webhook-style chat service providers
+// (discord, matrix, msteams, telegram): validating the Content-Type header
+// and pulling the `trigger_word`/`text` fields out of the parsed form. The
+// slack provider uses DetectContentType only; it parses its own message
+// fields, since it also accepts slash-command payloads.
+package webhookform
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+)
+
+// MessageModel is the trigger word/text pair common to every outgoing
+// webhook-style chat service integration.
+type MessageModel struct {
+	TriggerText string
+	Text        string
+}
+
+// DetectContentType extracts and validates the Content-Type header, returning
+// the parsed media type with any parameters (e.g. charset) stripped.
+func DetectContentType(header http.Header) (string, error) {
+	contentTypeStr := header.Get("Content-Type")
+	if contentTypeStr == "" {
+		return "", errors.New("Issue with Content-Type Header: No value found in HEADER for the key: Content-Type")
+	}
+
+	contentType, _, err := mime.ParseMediaType(contentTypeStr)
+	if err != nil {
+		return "", fmt.Errorf("Issue with parsing Content-Type Header: %s", err)
+	}
+	return contentType, nil
+}
+
+// ParseMessage parses r's form body (a no-op if it was already parsed, e.g.
+// by a signature check that had to consume r.Body first) and pulls the
+// `trigger_word`/`text` fields out of it.
+func ParseMessage(r *http.Request) (MessageModel, error) {
+	if err := r.ParseForm(); err != nil {
+		return MessageModel{}, fmt.Errorf("Failed to parse the request form: %s", err)
+	}
+
+	triggerText := r.PostForm.Get("trigger_word")
+	if triggerText == "" {
+		return MessageModel{}, errors.New("Missing required parameter: 'trigger_word'")
+	}
+
+	text := r.PostForm.Get("text")
+	if text == "" {
+		return MessageModel{}, errors.New("Missing required parameter: 'text'")
+	}
+
+	return MessageModel{
+		TriggerText: triggerText,
+		Text:        text,
+	}, nil
+}
@@ -0,0 +1,74 @@
+package webhookform
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DetectContentType(t *testing.T) {
+	t.Log("Proper Content-Type")
+	{
+		header := http.Header{"Content-Type": {"application/x-www-form-urlencoded"}}
+		contentType, err := DetectContentType(header)
+		require.NoError(t, err)
+		require.Equal(t, "application/x-www-form-urlencoded", contentType)
+	}
+	t.Log("Missing Content-Type")
+	{
+		contentType, err := DetectContentType(http.Header{})
+		require.EqualError(t, err, "Issue with Content-Type Header: No value found in HEADER for the key: Content-Type")
+		require.Equal(t, "", contentType)
+	}
+}
+
+func Test_ParseMessage(t *testing.T) {
+	t.Log("Proper Form content")
+	{
+		request := http.Request{}
+		form := url.Values{}
+		form.Add("trigger_word", "the trigger word")
+		form.Add("text", "the text")
+		request.PostForm = form
+
+		messageModel, err := ParseMessage(&request)
+		require.NoError(t, err)
+		require.Equal(t, MessageModel{TriggerText: "the trigger word", Text: "the text"}, messageModel)
+	}
+
+	t.Log("Missing trigger_word")
+	{
+		request := http.Request{}
+		form := url.Values{}
+		form.Add("text", "the text")
+		request.PostForm = form
+
+		_, err := ParseMessage(&request)
+		require.EqualError(t, err, "Missing required parameter: 'trigger_word'")
+	}
+
+	t.Log("Missing text")
+	{
+		request := http.Request{}
+		form := url.Values{}
+		form.Add("trigger_word", "the trigger word")
+		request.PostForm = form
+
+		_, err := ParseMessage(&request)
+		require.EqualError(t, err, "Missing required parameter: 'text'")
+	}
+
+	t.Log("Real, not-yet-parsed request body")
+	{
+		request := httptest.NewRequest(http.MethodPost, "/h/discord", strings.NewReader("trigger_word=the+trigger+word&text=the+text"))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		messageModel, err := ParseMessage(request)
+		require.NoError(t, err)
+		require.Equal(t, MessageModel{TriggerText: "the trigger word", Text: "the text"}, messageModel)
+	}
+}
@@ -0,0 +1,124 @@
+// Package discord implements the hookCommon.Provider interface for a Discord
+// bot/webhook relay that forwards chat messages using the same pipe-separated
+// trigger syntax as the slack package.
+package discord
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bitrise-io/bitrise-webhooks/bitriseapi"
+	hookCommon "github.com/bitrise-io/bitrise-webhooks/service/hook/common"
+	"github.com/bitrise-io/bitrise-webhooks/service/hook/common/textparams"
+	"github.com/bitrise-io/bitrise-webhooks/service/hook/common/webhookform"
+)
+
+// embed is a Discord webhook embed object, as documented at:
+// https://discord.com/developers/docs/resources/webhook#execute-webhook
+type embed struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Color       int    `json:"color"`
+}
+
+// WebhookRespModel is the body Discord's "Execute Webhook" endpoint expects.
+type WebhookRespModel struct {
+	Embeds []embed `json:"embeds"`
+}
+
+const (
+	colorSuccess = 0x2ECC71
+	colorFailure = 0xE74C3C
+)
+
+// HookProvider ...
+type HookProvider struct{}
+
+// TransformRequest ...
+func (hp HookProvider) TransformRequest(r *http.Request) hookCommon.TransformResultModel {
+	contentType, err := webhookform.DetectContentType(r.Header)
+	if err != nil {
+		return hookCommon.TransformResultModel{Error: err}
+	}
+	if contentType != "application/x-www-form-urlencoded" {
+		return hookCommon.TransformResultModel{
+			Error: fmt.Errorf("Content-Type is not supported: %s", contentType),
+		}
+	}
+
+	messageModel, err := webhookform.ParseMessage(r)
+	if err != nil {
+		return hookCommon.TransformResultModel{
+			Error: fmt.Errorf("Failed to parse the request/message: %s", err),
+		}
+	}
+
+	return textparams.Transform(messageModel.TriggerText, messageModel.Text)
+}
+
+// discordConvertor renders Trigger API results as Discord embeds.
+type discordConvertor struct{}
+
+func (discordConvertor) Success(resp bitriseapi.TriggerAPIResponseModel) interface{} {
+	return embed{
+		Title:       "Build triggered",
+		Description: fmt.Sprintf("%+v", resp),
+		Color:       colorSuccess,
+	}
+}
+
+func (discordConvertor) Failure(resp bitriseapi.TriggerAPIResponseModel) interface{} {
+	return embed{
+		Title:       "Trigger failed",
+		Description: fmt.Sprintf("%+v", resp),
+		Color:       colorFailure,
+	}
+}
+
+func (discordConvertor) Error(errMsg string) interface{} {
+	return embed{
+		Title:       "Error",
+		Description: errMsg,
+		Color:       colorFailure,
+	}
+}
+
+func (discordConvertor) Wrap(successes, faileds, errs []interface{}) (interface{}, string) {
+	embeds := make([]embed, 0, len(successes)+len(faileds)+len(errs))
+	for _, aPart := range successes {
+		embeds = append(embeds, aPart.(embed))
+	}
+	for _, aPart := range faileds {
+		embeds = append(embeds, aPart.(embed))
+	}
+	for _, aPart := range errs {
+		embeds = append(embeds, aPart.(embed))
+	}
+
+	return WebhookRespModel{Embeds: embeds}, "application/json"
+}
+
+// TransformResponse ...
+func (hp HookProvider) TransformResponse(input hookCommon.TransformResponseInputModel) hookCommon.TransformResponseModel {
+	return hookCommon.BuildTransformResponse(discordConvertor{}, input)
+}
+
+// TransformErrorMessageResponse ...
+func (hp HookProvider) TransformErrorMessageResponse(errMsg string) hookCommon.TransformResponseModel {
+	return hookCommon.TransformResponseModel{
+		Data: WebhookRespModel{Embeds: []embed{
+			{Title: "Error", Description: errMsg, Color: colorFailure},
+		}},
+		HTTPStatusCode: http.StatusOK,
+	}
+}
+
+// TransformSuccessMessageResponse ...
+func (hp HookProvider) TransformSuccessMessageResponse(msg string) hookCommon.TransformResponseModel {
+	return hookCommon.TransformResponseModel{
+		Data: WebhookRespModel{Embeds: []embed{
+			{Title: "Success", Description: msg, Color: colorSuccess},
+		}},
+		HTTPStatusCode: http.StatusOK,
+	}
+}
@@ -0,0 +1,62 @@
+package discord
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-webhooks/bitriseapi"
+	hookCommon "github.com/bitrise-io/bitrise-webhooks/service/hook/common"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HookProvider_TransformRequest(t *testing.T) {
+	provider := HookProvider{}
+
+	t.Log("Should be OK")
+	{
+		request := http.Request{
+			Header: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+		}
+		form := url.Values{}
+		form.Add("trigger_word", "bitrise:")
+		form.Add("text", "bitrise: branch:master")
+		request.PostForm = form
+
+		hookTransformResult := provider.TransformRequest(&request)
+		require.NoError(t, hookTransformResult.Error)
+		require.Equal(t, []bitriseapi.TriggerAPIParamsModel{
+			{BuildParams: bitriseapi.BuildParamsModel{Branch: "master"}},
+		}, hookTransformResult.TriggerAPIParams)
+	}
+
+	t.Log("Unsupported Content-Type")
+	{
+		request := http.Request{
+			Header: http.Header{"Content-Type": {"application/json"}},
+		}
+		hookTransformResult := provider.TransformRequest(&request)
+		require.EqualError(t, hookTransformResult.Error, "Content-Type is not supported: application/json")
+	}
+}
+
+func Test_HookProvider_TransformResponse(t *testing.T) {
+	provider := HookProvider{}
+
+	resp := provider.TransformResponse(hookCommon.TransformResponseInputModel{
+		SuccessTriggerResponses: []bitriseapi.TriggerAPIResponseModel{
+			{Status: "ok", Message: "triggered build", Service: "bitrise", AppSlug: "app-slug", BuildSlug: "build-slug"},
+		},
+		Errors: []string{"an error"},
+	})
+	require.Equal(t, 200, resp.HTTPStatusCode)
+
+	respModel, ok := resp.Data.(WebhookRespModel)
+	require.True(t, ok)
+	require.Len(t, respModel.Embeds, 2)
+	require.Equal(t, "Build triggered", respModel.Embeds[0].Title)
+	require.Equal(t, colorSuccess, respModel.Embeds[0].Color)
+	require.Equal(t, "Error", respModel.Embeds[1].Title)
+	require.Equal(t, "an error", respModel.Embeds[1].Description)
+	require.Equal(t, colorFailure, respModel.Embeds[1].Color)
+}
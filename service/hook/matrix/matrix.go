@@ -0,0 +1,127 @@
+// Package matrix implements the hookCommon.Provider interface for a Matrix
+// bot/bridge that forwards room messages using the same pipe-separated
+// trigger syntax as the slack package.
+package matrix
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-webhooks/bitriseapi"
+	hookCommon "github.com/bitrise-io/bitrise-webhooks/service/hook/common"
+	"github.com/bitrise-io/bitrise-webhooks/service/hook/common/textparams"
+	"github.com/bitrise-io/bitrise-webhooks/service/hook/common/webhookform"
+)
+
+// RoomMessageRespModel is an `m.notice` event body, as documented at:
+// https://spec.matrix.org/latest/client-server-api/#mnotice
+type RoomMessageRespModel struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+// HookProvider ...
+type HookProvider struct{}
+
+// TransformRequest ...
+func (hp HookProvider) TransformRequest(r *http.Request) hookCommon.TransformResultModel {
+	contentType, err := webhookform.DetectContentType(r.Header)
+	if err != nil {
+		return hookCommon.TransformResultModel{Error: err}
+	}
+	if contentType != "application/x-www-form-urlencoded" {
+		return hookCommon.TransformResultModel{
+			Error: fmt.Errorf("Content-Type is not supported: %s", contentType),
+		}
+	}
+
+	messageModel, err := webhookform.ParseMessage(r)
+	if err != nil {
+		return hookCommon.TransformResultModel{
+			Error: fmt.Errorf("Failed to parse the request/message: %s", err),
+		}
+	}
+
+	return textparams.Transform(messageModel.TriggerText, messageModel.Text)
+}
+
+// matrixConvertor renders Trigger API results as an `m.notice` with an HTML
+// formatted_body.
+type matrixConvertor struct{}
+
+func (matrixConvertor) Success(resp bitriseapi.TriggerAPIResponseModel) interface{} {
+	return fmt.Sprintf("* %+v", resp)
+}
+
+func (matrixConvertor) Failure(resp bitriseapi.TriggerAPIResponseModel) interface{} {
+	return fmt.Sprintf("* %+v", resp)
+}
+
+func (matrixConvertor) Error(errMsg string) interface{} {
+	return fmt.Sprintf("* %s", errMsg)
+}
+
+func (matrixConvertor) Wrap(successes, faileds, errs []interface{}) (interface{}, string) {
+	plainLines := []string{"Results:"}
+	htmlLines := []string{"<p>Results:</p>"}
+
+	appendSection := func(heading string, parts []interface{}) {
+		if len(parts) == 0 {
+			return
+		}
+		plainLines = append(plainLines, heading+":")
+		htmlLines = append(htmlLines, fmt.Sprintf("<p><strong>%s</strong>:</p><ul>", html.EscapeString(heading)))
+		for _, aPart := range parts {
+			line := aPart.(string)
+			plainLines = append(plainLines, line)
+			htmlLines = append(htmlLines, fmt.Sprintf("<li>%s</li>", html.EscapeString(line)))
+		}
+		htmlLines = append(htmlLines, "</ul>")
+	}
+
+	appendSection("Success", successes)
+	appendSection("Failed Triggers", faileds)
+	appendSection("Errors", errs)
+
+	return RoomMessageRespModel{
+		MsgType:       "m.notice",
+		Body:          strings.Join(plainLines, "\n"),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: strings.Join(htmlLines, ""),
+	}, "application/json"
+}
+
+// TransformResponse ...
+func (hp HookProvider) TransformResponse(input hookCommon.TransformResponseInputModel) hookCommon.TransformResponseModel {
+	return hookCommon.BuildTransformResponse(matrixConvertor{}, input)
+}
+
+// TransformErrorMessageResponse ...
+func (hp HookProvider) TransformErrorMessageResponse(errMsg string) hookCommon.TransformResponseModel {
+	return hookCommon.TransformResponseModel{
+		Data: RoomMessageRespModel{
+			MsgType:       "m.notice",
+			Body:          fmt.Sprintf("Error: %s", errMsg),
+			Format:        "org.matrix.custom.html",
+			FormattedBody: fmt.Sprintf("<p><strong>Error</strong>: %s</p>", html.EscapeString(errMsg)),
+		},
+		HTTPStatusCode: http.StatusOK,
+	}
+}
+
+// TransformSuccessMessageResponse ...
+func (hp HookProvider) TransformSuccessMessageResponse(msg string) hookCommon.TransformResponseModel {
+	return hookCommon.TransformResponseModel{
+		Data: RoomMessageRespModel{
+			MsgType:       "m.notice",
+			Body:          msg,
+			Format:        "org.matrix.custom.html",
+			FormattedBody: html.EscapeString(msg),
+		},
+		HTTPStatusCode: http.StatusOK,
+	}
+}
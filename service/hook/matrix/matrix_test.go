@@ -0,0 +1,47 @@
+package matrix
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-webhooks/bitriseapi"
+	hookCommon "github.com/bitrise-io/bitrise-webhooks/service/hook/common"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HookProvider_TransformRequest(t *testing.T) {
+	provider := HookProvider{}
+
+	request := http.Request{
+		Header: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+	}
+	form := url.Values{}
+	form.Add("trigger_word", "bitrise:")
+	form.Add("text", "bitrise: branch:master")
+	request.PostForm = form
+
+	hookTransformResult := provider.TransformRequest(&request)
+	require.NoError(t, hookTransformResult.Error)
+	require.Equal(t, []bitriseapi.TriggerAPIParamsModel{
+		{BuildParams: bitriseapi.BuildParamsModel{Branch: "master"}},
+	}, hookTransformResult.TriggerAPIParams)
+}
+
+func Test_HookProvider_TransformResponse(t *testing.T) {
+	provider := HookProvider{}
+
+	resp := provider.TransformResponse(hookCommon.TransformResponseInputModel{
+		SuccessTriggerResponses: []bitriseapi.TriggerAPIResponseModel{
+			{Status: "ok", Message: "triggered build", Service: "bitrise", AppSlug: "app-slug", BuildSlug: "build-slug"},
+		},
+	})
+
+	respModel, ok := resp.Data.(RoomMessageRespModel)
+	require.True(t, ok)
+	require.Equal(t, "m.notice", respModel.MsgType)
+	require.Equal(t, "org.matrix.custom.html", respModel.Format)
+	require.True(t, strings.Contains(respModel.FormattedBody, "<li>"))
+	require.True(t, strings.Contains(respModel.Body, "Success"))
+}
@@ -0,0 +1,134 @@
+// Package msteams implements the hookCommon.Provider interface for a
+// Microsoft Teams connector/bot relay that forwards chat messages using the
+// same pipe-separated trigger syntax as the slack package.
+package msteams
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bitrise-io/bitrise-webhooks/bitriseapi"
+	hookCommon "github.com/bitrise-io/bitrise-webhooks/service/hook/common"
+	"github.com/bitrise-io/bitrise-webhooks/service/hook/common/textparams"
+	"github.com/bitrise-io/bitrise-webhooks/service/hook/common/webhookform"
+)
+
+// section is a single MessageCard section, as documented at:
+// https://learn.microsoft.com/en-us/outlook/actionable-messages/message-card-reference
+type section struct {
+	ActivityTitle    string `json:"activityTitle"`
+	ActivitySubtitle string `json:"activitySubtitle,omitempty"`
+}
+
+// MessageCardRespModel is the body a Teams Incoming Webhook connector expects.
+type MessageCardRespModel struct {
+	Type       string    `json:"@type"`
+	Context    string    `json:"@context"`
+	ThemeColor string    `json:"themeColor"`
+	Summary    string    `json:"summary"`
+	Sections   []section `json:"sections"`
+}
+
+const (
+	colorSuccess = "2ECC71"
+	colorFailure = "E74C3C"
+)
+
+// HookProvider ...
+type HookProvider struct{}
+
+// TransformRequest ...
+func (hp HookProvider) TransformRequest(r *http.Request) hookCommon.TransformResultModel {
+	contentType, err := webhookform.DetectContentType(r.Header)
+	if err != nil {
+		return hookCommon.TransformResultModel{Error: err}
+	}
+	if contentType != "application/x-www-form-urlencoded" {
+		return hookCommon.TransformResultModel{
+			Error: fmt.Errorf("Content-Type is not supported: %s", contentType),
+		}
+	}
+
+	messageModel, err := webhookform.ParseMessage(r)
+	if err != nil {
+		return hookCommon.TransformResultModel{
+			Error: fmt.Errorf("Failed to parse the request/message: %s", err),
+		}
+	}
+
+	return textparams.Transform(messageModel.TriggerText, messageModel.Text)
+}
+
+// msteamsConvertor renders Trigger API results as a Teams MessageCard.
+type msteamsConvertor struct{}
+
+func (msteamsConvertor) Success(resp bitriseapi.TriggerAPIResponseModel) interface{} {
+	return section{ActivityTitle: "Build triggered", ActivitySubtitle: fmt.Sprintf("%+v", resp)}
+}
+
+func (msteamsConvertor) Failure(resp bitriseapi.TriggerAPIResponseModel) interface{} {
+	return section{ActivityTitle: "Trigger failed", ActivitySubtitle: fmt.Sprintf("%+v", resp)}
+}
+
+func (msteamsConvertor) Error(errMsg string) interface{} {
+	return section{ActivityTitle: "Error", ActivitySubtitle: errMsg}
+}
+
+func (msteamsConvertor) Wrap(successes, faileds, errs []interface{}) (interface{}, string) {
+	sections := make([]section, 0, len(successes)+len(faileds)+len(errs))
+	for _, aPart := range successes {
+		sections = append(sections, aPart.(section))
+	}
+	for _, aPart := range faileds {
+		sections = append(sections, aPart.(section))
+	}
+	for _, aPart := range errs {
+		sections = append(sections, aPart.(section))
+	}
+
+	themeColor := colorSuccess
+	if len(faileds) > 0 || len(errs) > 0 {
+		themeColor = colorFailure
+	}
+
+	return MessageCardRespModel{
+		Type:       "MessageCard",
+		Context:    "http://schema.org/extensions",
+		ThemeColor: themeColor,
+		Summary:    "Bitrise build trigger results",
+		Sections:   sections,
+	}, "application/json"
+}
+
+// TransformResponse ...
+func (hp HookProvider) TransformResponse(input hookCommon.TransformResponseInputModel) hookCommon.TransformResponseModel {
+	return hookCommon.BuildTransformResponse(msteamsConvertor{}, input)
+}
+
+// TransformErrorMessageResponse ...
+func (hp HookProvider) TransformErrorMessageResponse(errMsg string) hookCommon.TransformResponseModel {
+	return hookCommon.TransformResponseModel{
+		Data: MessageCardRespModel{
+			Type:       "MessageCard",
+			Context:    "http://schema.org/extensions",
+			ThemeColor: colorFailure,
+			Summary:    "Bitrise build trigger error",
+			Sections:   []section{{ActivityTitle: "Error", ActivitySubtitle: errMsg}},
+		},
+		HTTPStatusCode: http.StatusOK,
+	}
+}
+
+// TransformSuccessMessageResponse ...
+func (hp HookProvider) TransformSuccessMessageResponse(msg string) hookCommon.TransformResponseModel {
+	return hookCommon.TransformResponseModel{
+		Data: MessageCardRespModel{
+			Type:       "MessageCard",
+			Context:    "http://schema.org/extensions",
+			ThemeColor: colorSuccess,
+			Summary:    "Bitrise build trigger success",
+			Sections:   []section{{ActivityTitle: "Success", ActivitySubtitle: msg}},
+		},
+		HTTPStatusCode: http.StatusOK,
+	}
+}
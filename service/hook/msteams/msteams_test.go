@@ -0,0 +1,73 @@
+package msteams
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-webhooks/bitriseapi"
+	hookCommon "github.com/bitrise-io/bitrise-webhooks/service/hook/common"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HookProvider_TransformRequest(t *testing.T) {
+	provider := HookProvider{}
+
+	t.Log("Should be OK")
+	{
+		request := http.Request{
+			Header: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+		}
+		form := url.Values{}
+		form.Add("trigger_word", "bitrise:")
+		form.Add("text", "bitrise: branch:master")
+		request.PostForm = form
+
+		hookTransformResult := provider.TransformRequest(&request)
+		require.NoError(t, hookTransformResult.Error)
+		require.Equal(t, []bitriseapi.TriggerAPIParamsModel{
+			{BuildParams: bitriseapi.BuildParamsModel{Branch: "master"}},
+		}, hookTransformResult.TriggerAPIParams)
+	}
+
+	t.Log("Missing trigger_word")
+	{
+		request := http.Request{
+			Header: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+		}
+		form := url.Values{}
+		form.Add("text", "branch:master")
+		request.PostForm = form
+
+		hookTransformResult := provider.TransformRequest(&request)
+		require.EqualError(t, hookTransformResult.Error, "Failed to parse the request/message: Missing required parameter: 'trigger_word'")
+	}
+}
+
+func Test_HookProvider_TransformResponse(t *testing.T) {
+	provider := HookProvider{}
+
+	t.Log("Success only - green theme")
+	{
+		resp := provider.TransformResponse(hookCommon.TransformResponseInputModel{
+			SuccessTriggerResponses: []bitriseapi.TriggerAPIResponseModel{
+				{Status: "ok", Message: "triggered build", Service: "bitrise", AppSlug: "app-slug", BuildSlug: "build-slug"},
+			},
+		})
+		respModel, ok := resp.Data.(MessageCardRespModel)
+		require.True(t, ok)
+		require.Equal(t, colorSuccess, respModel.ThemeColor)
+		require.Len(t, respModel.Sections, 1)
+	}
+
+	t.Log("Errors present - red theme")
+	{
+		resp := provider.TransformResponse(hookCommon.TransformResponseInputModel{
+			Errors: []string{"an error"},
+		})
+		respModel, ok := resp.Data.(MessageCardRespModel)
+		require.True(t, ok)
+		require.Equal(t, colorFailure, respModel.ThemeColor)
+		require.Equal(t, "Error", respModel.Sections[0].ActivityTitle)
+	}
+}
@@ -0,0 +1,378 @@
+// Package slack implements the hookCommon.Provider interface for
+// Slack outgoing webhooks (and, increasingly, their replacements).
+package slack
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/bitrise-io/bitrise-webhooks/bitriseapi"
+	hookCommon "github.com/bitrise-io/bitrise-webhooks/service/hook/common"
+	"github.com/bitrise-io/bitrise-webhooks/service/hook/common/textparams"
+	"github.com/bitrise-io/bitrise-webhooks/service/hook/common/webhookform"
+)
+
+const (
+	// signingSecretEnvKey is the env var through which the Slack
+	// signing secret (from the app's "Basic Information" page) is configured.
+	// When unset, signature verification is skipped and the legacy
+	// token-based check (if configured) is used instead.
+	signingSecretEnvKey = "SLACK_SIGNING_SECRET"
+	// legacyTokenEnvKey is the env var holding the shared token Slack's
+	// (deprecated) outgoing webhooks send in the `token` form field.
+	legacyTokenEnvKey = "SLACK_WEBHOOK_SECRET_TOKEN"
+
+	signingVersion       = "v0"
+	maxSigningClockSkew  = 5 * time.Minute
+	slackSignatureHeader = "X-Slack-Signature"
+	slackTimestampHeader = "X-Slack-Request-Timestamp"
+)
+
+// MessageModel ...
+type MessageModel struct {
+	TriggerText string
+	Text        string
+	// ResponseURL is set for slash-command payloads and lets the trigger
+	// response be delivered asynchronously instead of in the sync HTTP body.
+	ResponseURL string
+}
+
+// TextObject is a Block Kit composition text object:
+// https://api.slack.com/reference/block-kit/composition-objects#text
+type TextObject struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// ButtonElement is a Block Kit `button` interactive element:
+// https://api.slack.com/reference/block-kit/block-elements#button
+type ButtonElement struct {
+	Type string     `json:"type"`
+	Text TextObject `json:"text"`
+	URL  string     `json:"url,omitempty"`
+}
+
+// Block is a single Block Kit block. Only `section`, `context`, `divider`
+// and `actions` are used by this package: https://api.slack.com/block-kit
+type Block struct {
+	Type     string        `json:"type"`
+	Text     *TextObject   `json:"text,omitempty"`
+	Elements []interface{} `json:"elements,omitempty"`
+}
+
+// Attachment is Slack's legacy (pre-Block Kit) attachment format, kept for
+// clients that don't render blocks.
+type Attachment struct {
+	Color string `json:"color,omitempty"`
+	Text  string `json:"text,omitempty"`
+}
+
+// OutgoingWebhookRespModel ...
+type OutgoingWebhookRespModel struct {
+	Text        string       `json:"text,omitempty"`
+	Blocks      []Block      `json:"blocks,omitempty"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// MarshalJSON omits Text whenever Blocks are set: Slack only renders `text`
+// as a fallback/notification string once `blocks` is present, so sending
+// both is redundant.
+func (m OutgoingWebhookRespModel) MarshalJSON() ([]byte, error) {
+	type alias OutgoingWebhookRespModel
+	a := alias(m)
+	if len(a.Blocks) > 0 {
+		a.Text = ""
+	}
+	return json.Marshal(a)
+}
+
+// HookProvider ...
+type HookProvider struct{}
+
+// detectContentType is kept as a thin wrapper around
+// webhookform.DetectContentType, which is now shared with the other
+// chat-service hook providers.
+func detectContentType(header http.Header) (string, error) {
+	return webhookform.DetectContentType(header)
+}
+
+// createMessageModelFromFormRequest parses r's form body (a no-op if it was
+// already parsed, e.g. by the signing-secret branch that has to consume
+// r.Body for the HMAC check first) and builds a MessageModel from it.
+func createMessageModelFromFormRequest(r *http.Request) (MessageModel, error) {
+	if err := r.ParseForm(); err != nil {
+		return MessageModel{}, fmt.Errorf("Failed to parse the request form: %s", err)
+	}
+
+	// Slash commands (the replacement for deprecated outgoing webhooks) send
+	// their trigger in the `command` field (e.g. "/bitrise") instead of
+	// `trigger_word`.
+	triggerText := r.PostForm.Get("trigger_word")
+	if triggerText == "" {
+		triggerText = r.PostForm.Get("command")
+	}
+	if triggerText == "" {
+		return MessageModel{}, errors.New("Missing required parameter: 'trigger_word'")
+	}
+
+	text := r.PostForm.Get("text")
+	if text == "" {
+		return MessageModel{}, errors.New("Missing required parameter: 'text'")
+	}
+
+	return MessageModel{
+		TriggerText: triggerText,
+		Text:        text,
+		ResponseURL: r.PostForm.Get("response_url"),
+	}, nil
+}
+
+// collectParamsFromPipeSeparatedText is kept as a thin wrapper around
+// textparams.CollectParams, which is now shared with the other chat-service
+// hook providers.
+func collectParamsFromPipeSeparatedText(text string) map[string]string {
+	return textparams.CollectParams(text)
+}
+
+// transformOutgoingWebhookMessage is kept as a thin wrapper around
+// textparams.Transform, which is now shared with the other chat-service hook
+// providers.
+func transformOutgoingWebhookMessage(webhookMsg MessageModel) hookCommon.TransformResultModel {
+	return textparams.Transform(webhookMsg.TriggerText, webhookMsg.Text)
+}
+
+// verifySlackSignature implements Slack's v0 signing scheme:
+// https://api.slack.com/authentication/verifying-requests-from-slack
+func verifySlackSignature(header http.Header, rawBody []byte, signingSecret string) error {
+	timestampStr := header.Get(slackTimestampHeader)
+	signature := header.Get(slackSignatureHeader)
+	if timestampStr == "" || signature == "" {
+		return hookCommon.NewAuthenticationError("Missing Slack signature headers")
+	}
+
+	timestampUnix, err := strconv.ParseInt(timestampStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid %s header: %s", slackTimestampHeader, err)
+	}
+
+	clockSkew := time.Since(time.Unix(timestampUnix, 0))
+	if clockSkew > maxSigningClockSkew || clockSkew < -maxSigningClockSkew {
+		return hookCommon.NewAuthenticationError("Slack request timestamp is too old, possible replay attack")
+	}
+
+	baseString := fmt.Sprintf("%s:%s:%s", signingVersion, timestampStr, rawBody)
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	if _, err := mac.Write([]byte(baseString)); err != nil {
+		return fmt.Errorf("Failed to compute Slack signature: %s", err)
+	}
+	expectedSignature := signingVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expectedSignature), []byte(signature)) {
+		return hookCommon.NewAuthenticationError("Slack signature verification failed")
+	}
+
+	return nil
+}
+
+func verifyLegacyToken(r *http.Request, expectedToken string) error {
+	if err := r.ParseForm(); err != nil {
+		return fmt.Errorf("Failed to parse the request form: %s", err)
+	}
+	if r.PostForm.Get("token") != expectedToken {
+		return hookCommon.NewAuthenticationError("Slack legacy token verification failed")
+	}
+	return nil
+}
+
+// TransformRequest ...
+func (hp HookProvider) TransformRequest(r *http.Request) hookCommon.TransformResultModel {
+	if signingSecret := os.Getenv(signingSecretEnvKey); signingSecret != "" {
+		rawBody, err := io.ReadAll(r.Body)
+		if err != nil {
+			return hookCommon.TransformResultModel{Error: fmt.Errorf("Failed to read request body: %s", err)}
+		}
+		r.Body = io.NopCloser(bytes.NewReader(rawBody))
+
+		if err := verifySlackSignature(r.Header, rawBody, signingSecret); err != nil {
+			return hookCommon.TransformResultModel{Error: err}
+		}
+
+		if err := r.ParseForm(); err != nil {
+			return hookCommon.TransformResultModel{Error: fmt.Errorf("Failed to parse the request form: %s", err)}
+		}
+	} else if legacyToken := os.Getenv(legacyTokenEnvKey); legacyToken != "" {
+		if err := verifyLegacyToken(r, legacyToken); err != nil {
+			return hookCommon.TransformResultModel{Error: err}
+		}
+	}
+
+	contentType, err := detectContentType(r.Header)
+	if err != nil {
+		return hookCommon.TransformResultModel{Error: err}
+	}
+	if contentType != "application/x-www-form-urlencoded" {
+		return hookCommon.TransformResultModel{
+			Error: fmt.Errorf("Content-Type is not supported: %s", contentType),
+		}
+	}
+
+	messageModel, err := createMessageModelFromFormRequest(r)
+	if err != nil {
+		return hookCommon.TransformResultModel{
+			Error: fmt.Errorf("Failed to parse the request/message: %s", err),
+		}
+	}
+
+	result := transformOutgoingWebhookMessage(messageModel)
+	result.ResponseURL = messageModel.ResponseURL
+	return result
+}
+
+const buildURLFormat = "https://app.bitrise.io/build/%s"
+
+func sectionBlock(mrkdwnText string) Block {
+	return Block{Type: "section", Text: &TextObject{Type: "mrkdwn", Text: mrkdwnText}}
+}
+
+func contextBlock(service string) Block {
+	return Block{
+		Type: "context",
+		Elements: []interface{}{
+			TextObject{Type: "mrkdwn", Text: fmt.Sprintf("Service: *%s*", service)},
+		},
+	}
+}
+
+func buildLinkActionsBlock(buildSlug string) Block {
+	return Block{
+		Type: "actions",
+		Elements: []interface{}{
+			ButtonElement{
+				Type: "button",
+				Text: TextObject{Type: "plain_text", Text: "View Build"},
+				URL:  fmt.Sprintf(buildURLFormat, buildSlug),
+			},
+		},
+	}
+}
+
+// triggerBlocks renders a single triggered build (successful or failed) as a
+// leading mrkdwn `section` block, a build-link button when BuildSlug is set,
+// and a context block naming the service when Service is set. The leading
+// block is a `section`, not a Block Kit `header` block.
+func triggerBlocks(emoji, headline string, resp bitriseapi.TriggerAPIResponseModel) []Block {
+	blocks := []Block{sectionBlock(fmt.Sprintf("%s *%s* %s", emoji, headline, resp.Message))}
+	if resp.BuildSlug != "" {
+		blocks = append(blocks, buildLinkActionsBlock(resp.BuildSlug))
+	}
+	if resp.Service != "" {
+		blocks = append(blocks, contextBlock(resp.Service))
+	}
+	return blocks
+}
+
+// slackConvertor renders Trigger API results as Slack Block Kit blocks:
+// https://api.slack.com/block-kit
+type slackConvertor struct{}
+
+func (slackConvertor) Success(resp bitriseapi.TriggerAPIResponseModel) interface{} {
+	return triggerBlocks(":white_check_mark:", "Success!", resp)
+}
+
+func (slackConvertor) Failure(resp bitriseapi.TriggerAPIResponseModel) interface{} {
+	return triggerBlocks(":red_circle:", "Failed!", resp)
+}
+
+func (slackConvertor) Error(errMsg string) interface{} {
+	return []Block{sectionBlock(fmt.Sprintf(":red_circle: *Error:* %s", errMsg))}
+}
+
+func (slackConvertor) Wrap(successes, faileds, errs []interface{}) (interface{}, string) {
+	var groups [][]Block
+	for _, aPart := range successes {
+		groups = append(groups, aPart.([]Block))
+	}
+	for _, aPart := range faileds {
+		groups = append(groups, aPart.([]Block))
+	}
+	for _, aPart := range errs {
+		groups = append(groups, aPart.([]Block))
+	}
+
+	var blocks []Block
+	for i, group := range groups {
+		if i > 0 {
+			blocks = append(blocks, Block{Type: "divider"})
+		}
+		blocks = append(blocks, group...)
+	}
+
+	return OutgoingWebhookRespModel{Blocks: blocks}, "application/json"
+}
+
+// TransformResponse ...
+func (hp HookProvider) TransformResponse(input hookCommon.TransformResponseInputModel) hookCommon.TransformResponseModel {
+	resp := hookCommon.BuildTransformResponse(slackConvertor{}, input)
+
+	if input.ResponseURL != "" {
+		// Slash commands get a 3 second window for the sync response; deliver
+		// the real result as a Slack "delayed response" instead (up to 30 min).
+		respModel, _ := resp.Data.(OutgoingWebhookRespModel)
+		go deliverDelayedResponse(input.ResponseURL, respModel)
+		return hookCommon.TransformResponseModel{
+			Data:           OutgoingWebhookRespModel{},
+			HTTPStatusCode: http.StatusOK,
+		}
+	}
+
+	return resp
+}
+
+// TransformErrorMessageResponse ...
+func (hp HookProvider) TransformErrorMessageResponse(errMsg string) hookCommon.TransformResponseModel {
+	return hookCommon.TransformResponseModel{
+		Data:           OutgoingWebhookRespModel{Text: fmt.Sprintf("*[!] Error*: %s", errMsg)},
+		HTTPStatusCode: http.StatusOK,
+	}
+}
+
+// TransformSuccessMessageResponse ...
+func (hp HookProvider) TransformSuccessMessageResponse(msg string) hookCommon.TransformResponseModel {
+	return hookCommon.TransformResponseModel{
+		Data:           OutgoingWebhookRespModel{Text: msg},
+		HTTPStatusCode: http.StatusOK,
+	}
+}
+
+// deliverDelayedResponse POSTs the final result to a Slack slash-command's
+// response_url, as documented at:
+// https://api.slack.com/interactivity/slash-commands#responding_to_commands
+func deliverDelayedResponse(responseURL string, respModel OutgoingWebhookRespModel) {
+	bodyBytes, err := json.Marshal(respModel)
+	if err != nil {
+		log.Printf("slack: failed to marshal delayed response: %s", err)
+		return
+	}
+
+	resp, err := http.Post(responseURL, "application/json", bytes.NewReader(bodyBytes))
+	if err != nil {
+		log.Printf("slack: failed to deliver delayed response: %s", err)
+		return
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("slack: failed to close delayed response body: %s", err)
+		}
+	}()
+}
@@ -1,15 +1,33 @@
 package slack
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/bitrise-io/bitrise-webhooks/bitriseapi"
 	hookCommon "github.com/bitrise-io/bitrise-webhooks/service/hook/common"
 	"github.com/stretchr/testify/require"
 )
 
+func signSlackRequest(signingSecret, timestamp string, rawBody []byte) string {
+	baseString := fmt.Sprintf("v0:%s:%s", timestamp, rawBody)
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte(baseString))
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
 func Test_detectContentType(t *testing.T) {
 	t.Log("Proper Content-Type")
 	{
@@ -340,6 +358,25 @@ func Test_HookProvider_TransformRequest(t *testing.T) {
 		require.False(t, hookTransformResult.ShouldSkip)
 		require.EqualError(t, hookTransformResult.Error, "Failed to parse the request/message: Missing required parameter: 'text'")
 	}
+
+	t.Log("Real, not-yet-parsed request body - no signing secret/legacy token configured")
+	{
+		t.Setenv(signingSecretEnvKey, "")
+		t.Setenv(legacyTokenEnvKey, "")
+
+		request := httptest.NewRequest(http.MethodPost, "/h/slack", strings.NewReader("trigger_word=bitrise%3A&text=bitrise%3A+branch%3Amaster"))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		hookTransformResult := provider.TransformRequest(request)
+		require.NoError(t, hookTransformResult.Error)
+		require.Equal(t, []bitriseapi.TriggerAPIParamsModel{
+			{
+				BuildParams: bitriseapi.BuildParamsModel{
+					Branch: "master",
+				},
+			},
+		}, hookTransformResult.TriggerAPIParams)
+	}
 }
 
 func Test_HookProvider_TransformResponse(t *testing.T) {
@@ -347,7 +384,7 @@ func Test_HookProvider_TransformResponse(t *testing.T) {
 
 	t.Log("Single success")
 	{
-		baseRespModel := hookCommon.TransformResponseInputModel{
+		resp := provider.TransformResponse(hookCommon.TransformResponseInputModel{
 			SuccessTriggerResponses: []bitriseapi.TriggerAPIResponseModel{
 				{
 					Status:    "ok",
@@ -357,77 +394,84 @@ func Test_HookProvider_TransformResponse(t *testing.T) {
 					BuildSlug: "build-slug",
 				},
 			},
-		}
+		})
+		require.Equal(t, 200, resp.HTTPStatusCode)
 
-		resp := provider.TransformResponse(baseRespModel)
-		require.Equal(t, hookCommon.TransformResponseModel{
-			Data: OutgoingWebhookRespModel{
-				Text: `Results:
-*Success!* Details:
-* {Status:ok Message:triggered build Service:bitrise AppSlug:app-slug BuildSlug:build-slug}`,
-			},
-			HTTPStatusCode: 200,
-		}, resp)
+		respModel, ok := resp.Data.(OutgoingWebhookRespModel)
+		require.True(t, ok)
+
+		bodyBytes, err := json.Marshal(respModel)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"blocks": [
+				{"type": "section", "text": {"type": "mrkdwn", "text": ":white_check_mark: *Success!* triggered build"}},
+				{"type": "actions", "elements": [
+					{"type": "button", "text": {"type": "plain_text", "text": "View Build"}, "url": "https://app.bitrise.io/build/build-slug"}
+				]},
+				{"type": "context", "elements": [
+					{"type": "mrkdwn", "text": "Service: *bitrise*"}
+				]}
+			]
+		}`, string(bodyBytes))
 	}
 
-	t.Log("Single failed trigger")
+	t.Log("Mixed success and failure - divider between them")
 	{
-		baseRespModel := hookCommon.TransformResponseInputModel{
-			FailedTriggerResponses: []bitriseapi.TriggerAPIResponseModel{
-				{
-					Status:    "error",
-					Message:   "some error happened",
-					Service:   "bitrise",
-					AppSlug:   "app-slug",
-					BuildSlug: "build-slug",
-				},
+		resp := provider.TransformResponse(hookCommon.TransformResponseInputModel{
+			SuccessTriggerResponses: []bitriseapi.TriggerAPIResponseModel{
+				{Message: "triggered build", Service: "bitrise", BuildSlug: "build-slug-1"},
 			},
-		}
-
-		resp := provider.TransformResponse(baseRespModel)
-		require.Equal(t, hookCommon.TransformResponseModel{
-			Data: OutgoingWebhookRespModel{
-				Text: `Results:
-*[!] Failed Triggers*:
-* {Status:error Message:some error happened Service:bitrise AppSlug:app-slug BuildSlug:build-slug}`,
+			FailedTriggerResponses: []bitriseapi.TriggerAPIResponseModel{
+				{Message: "some error happened", Service: "bitrise", BuildSlug: "build-slug-2"},
 			},
-			HTTPStatusCode: 200,
-		}, resp)
-	}
+		})
+		require.Equal(t, 200, resp.HTTPStatusCode)
 
-	t.Log("Single error")
-	{
-		baseRespModel := hookCommon.TransformResponseInputModel{
-			Errors: []string{"a single error"},
-		}
+		respModel, ok := resp.Data.(OutgoingWebhookRespModel)
+		require.True(t, ok)
 
-		resp := provider.TransformResponse(baseRespModel)
-		require.Equal(t, hookCommon.TransformResponseModel{
-			Data: OutgoingWebhookRespModel{
-				Text: `Results:
-*[!] Errors*:
-* a single error`,
-			},
-			HTTPStatusCode: 200,
-		}, resp)
+		bodyBytes, err := json.Marshal(respModel)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"blocks": [
+				{"type": "section", "text": {"type": "mrkdwn", "text": ":white_check_mark: *Success!* triggered build"}},
+				{"type": "actions", "elements": [
+					{"type": "button", "text": {"type": "plain_text", "text": "View Build"}, "url": "https://app.bitrise.io/build/build-slug-1"}
+				]},
+				{"type": "context", "elements": [
+					{"type": "mrkdwn", "text": "Service: *bitrise*"}
+				]},
+				{"type": "divider"},
+				{"type": "section", "text": {"type": "mrkdwn", "text": ":red_circle: *Failed!* some error happened"}},
+				{"type": "actions", "elements": [
+					{"type": "button", "text": {"type": "plain_text", "text": "View Build"}, "url": "https://app.bitrise.io/build/build-slug-2"}
+				]},
+				{"type": "context", "elements": [
+					{"type": "mrkdwn", "text": "Service: *bitrise*"}
+				]}
+			]
+		}`, string(bodyBytes))
 	}
 
-	t.Log("Multiple errors")
+	t.Log("Errors only - no build-link button or context, divider between them")
 	{
-		baseRespModel := hookCommon.TransformResponseInputModel{
+		resp := provider.TransformResponse(hookCommon.TransformResponseInputModel{
 			Errors: []string{"first error", "Second Error"},
-		}
+		})
+		require.Equal(t, 200, resp.HTTPStatusCode)
 
-		resp := provider.TransformResponse(baseRespModel)
-		require.Equal(t, hookCommon.TransformResponseModel{
-			Data: OutgoingWebhookRespModel{
-				Text: `Results:
-*[!] Errors*:
-* first error
-* Second Error`,
-			},
-			HTTPStatusCode: 200,
-		}, resp)
+		respModel, ok := resp.Data.(OutgoingWebhookRespModel)
+		require.True(t, ok)
+
+		bodyBytes, err := json.Marshal(respModel)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"blocks": [
+				{"type": "section", "text": {"type": "mrkdwn", "text": ":red_circle: *Error:* first error"}},
+				{"type": "divider"},
+				{"type": "section", "text": {"type": "mrkdwn", "text": ":red_circle: *Error:* Second Error"}}
+			]
+		}`, string(bodyBytes))
 	}
 }
 
@@ -457,4 +501,285 @@ func Test_HookProvider_TransformSuccessMessageResponse(t *testing.T) {
 			HTTPStatusCode: 200,
 		}, resp)
 	}
-}
\ No newline at end of file
+}
+
+func Test_HookProvider_TransformRequest_SigningSecret(t *testing.T) {
+	provider := HookProvider{}
+	signingSecret := "test-signing-secret"
+
+	newSignedRequest := func(timestamp string, body string, tamperSignature bool) *http.Request {
+		signature := signSlackRequest(signingSecret, timestamp, []byte(body))
+		if tamperSignature {
+			signature = "v0=0000000000000000000000000000000000000000000000000000000000000000"
+		}
+		request := http.Request{
+			Method: http.MethodPost,
+			Header: http.Header{
+				"Content-Type":       {"application/x-www-form-urlencoded"},
+				slackTimestampHeader: {timestamp},
+				slackSignatureHeader: {signature},
+			},
+			Body: io.NopCloser(bytes.NewReader([]byte(body))),
+		}
+		return &request
+	}
+
+	t.Log("Valid signature - should be OK")
+	{
+		t.Setenv(signingSecretEnvKey, signingSecret)
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		request := newSignedRequest(timestamp, "trigger_word=bitrise%3A&text=bitrise%3A+branch%3Amaster", false)
+
+		hookTransformResult := provider.TransformRequest(request)
+		require.NoError(t, hookTransformResult.Error)
+		require.Equal(t, []bitriseapi.TriggerAPIParamsModel{
+			{
+				BuildParams: bitriseapi.BuildParamsModel{
+					Branch: "master",
+				},
+			},
+		}, hookTransformResult.TriggerAPIParams)
+	}
+
+	t.Log("Invalid signature - should fail")
+	{
+		t.Setenv(signingSecretEnvKey, signingSecret)
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		request := newSignedRequest(timestamp, "trigger_word=bitrise%3A&text=bitrise%3A+branch%3Amaster", true)
+
+		hookTransformResult := provider.TransformRequest(request)
+		require.EqualError(t, hookTransformResult.Error, "Slack signature verification failed")
+		require.True(t, hookCommon.IsAuthenticationError(hookTransformResult.Error))
+	}
+
+	t.Log("Stale timestamp - should fail")
+	{
+		t.Setenv(signingSecretEnvKey, signingSecret)
+		timestamp := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+		request := newSignedRequest(timestamp, "trigger_word=bitrise%3A&text=bitrise%3A+branch%3Amaster", false)
+
+		hookTransformResult := provider.TransformRequest(request)
+		require.EqualError(t, hookTransformResult.Error, "Slack request timestamp is too old, possible replay attack")
+		require.True(t, hookCommon.IsAuthenticationError(hookTransformResult.Error))
+	}
+
+	t.Log("No signing secret configured - legacy flow, PostForm already parsed")
+	{
+		t.Setenv(signingSecretEnvKey, "")
+		request := http.Request{
+			Header: http.Header{
+				"Content-Type": {"application/x-www-form-urlencoded"},
+			},
+		}
+		form := url.Values{}
+		form.Add("trigger_word", "bitrise:")
+		form.Add("text", "bitrise: branch:master")
+		request.PostForm = form
+
+		hookTransformResult := provider.TransformRequest(&request)
+		require.NoError(t, hookTransformResult.Error)
+	}
+}
+
+func Test_HookProvider_TransformRequest_LegacyToken(t *testing.T) {
+	provider := HookProvider{}
+	legacyToken := "test-legacy-token"
+
+	t.Log("Valid legacy token, real not-yet-parsed request body - should be OK")
+	{
+		t.Setenv(signingSecretEnvKey, "")
+		t.Setenv(legacyTokenEnvKey, legacyToken)
+
+		request := httptest.NewRequest(http.MethodPost, "/h/slack", strings.NewReader("token="+legacyToken+"&trigger_word=bitrise%3A&text=bitrise%3A+branch%3Amaster"))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		hookTransformResult := provider.TransformRequest(request)
+		require.NoError(t, hookTransformResult.Error)
+		require.Equal(t, []bitriseapi.TriggerAPIParamsModel{
+			{
+				BuildParams: bitriseapi.BuildParamsModel{
+					Branch: "master",
+				},
+			},
+		}, hookTransformResult.TriggerAPIParams)
+	}
+
+	t.Log("Invalid legacy token - should fail")
+	{
+		t.Setenv(signingSecretEnvKey, "")
+		t.Setenv(legacyTokenEnvKey, legacyToken)
+
+		request := httptest.NewRequest(http.MethodPost, "/h/slack", strings.NewReader("token=wrong-token&trigger_word=bitrise%3A&text=bitrise%3A+branch%3Amaster"))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		hookTransformResult := provider.TransformRequest(request)
+		require.EqualError(t, hookTransformResult.Error, "Slack legacy token verification failed")
+		require.True(t, hookCommon.IsAuthenticationError(hookTransformResult.Error))
+	}
+}
+
+func Test_createMessageModelFromFormRequest_SlashCommand(t *testing.T) {
+	t.Log("Slash command - command used in place of trigger_word")
+	{
+		request := http.Request{}
+		form := url.Values{}
+		form.Add("command", "/bitrise")
+		form.Add("text", "branch:master")
+		request.PostForm = form
+
+		messageModel, err := createMessageModelFromFormRequest(&request)
+		require.NoError(t, err)
+		require.Equal(t, MessageModel{
+			TriggerText: "/bitrise",
+			Text:        "branch:master",
+		}, messageModel)
+	}
+
+	t.Log("Slash command - response_url is recorded")
+	{
+		request := http.Request{}
+		form := url.Values{}
+		form.Add("command", "/bitrise")
+		form.Add("text", "branch:master")
+		form.Add("response_url", "https://hooks.slack.com/commands/T0/1/abc")
+		request.PostForm = form
+
+		messageModel, err := createMessageModelFromFormRequest(&request)
+		require.NoError(t, err)
+		require.Equal(t, MessageModel{
+			TriggerText: "/bitrise",
+			Text:        "branch:master",
+			ResponseURL: "https://hooks.slack.com/commands/T0/1/abc",
+		}, messageModel)
+	}
+}
+
+func Test_HookProvider_TransformResponse_DelayedResponse(t *testing.T) {
+	t.Log("No response_url - result returned synchronously")
+	{
+		provider := HookProvider{}
+		resp := provider.TransformResponse(hookCommon.TransformResponseInputModel{
+			SuccessTriggerResponses: []bitriseapi.TriggerAPIResponseModel{
+				{Status: "ok", Message: "triggered build", Service: "bitrise", AppSlug: "app-slug", BuildSlug: "build-slug"},
+			},
+		})
+		require.Equal(t, 200, resp.HTTPStatusCode)
+
+		respModel, ok := resp.Data.(OutgoingWebhookRespModel)
+		require.True(t, ok)
+		require.Len(t, respModel.Blocks, 3)
+		require.Equal(t, "section", respModel.Blocks[0].Type)
+	}
+
+	t.Log("With response_url - result delivered asynchronously, sync body is empty")
+	{
+		received := make(chan OutgoingWebhookRespModel, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var respModel OutgoingWebhookRespModel
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&respModel))
+			received <- respModel
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		request := http.Request{
+			Header: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+		}
+		form := url.Values{}
+		form.Add("command", "/bitrise")
+		form.Add("text", "branch:master")
+		form.Add("response_url", server.URL)
+		request.PostForm = form
+
+		provider := HookProvider{}
+		transformResult := provider.TransformRequest(&request)
+		require.NoError(t, transformResult.Error)
+
+		resp := provider.TransformResponse(hookCommon.TransformResponseInputModel{
+			SuccessTriggerResponses: []bitriseapi.TriggerAPIResponseModel{
+				{Status: "ok", Message: "triggered build", Service: "bitrise", AppSlug: "app-slug", BuildSlug: "build-slug"},
+			},
+			ResponseURL: transformResult.ResponseURL,
+		})
+		require.Equal(t, 200, resp.HTTPStatusCode)
+		require.Equal(t, OutgoingWebhookRespModel{}, resp.Data)
+
+		select {
+		case respModel := <-received:
+			require.Empty(t, respModel.Text)
+			require.Len(t, respModel.Blocks, 3)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for delayed response delivery")
+		}
+	}
+}
+
+func Test_transformOutgoingWebhookMessage_WorkflowAndEnvs(t *testing.T) {
+	t.Log("Workflow parameter")
+	{
+		webhookMsg := MessageModel{
+			TriggerText: "bitrise -",
+			Text:        "bitrise - branch: main | workflow: deploy",
+		}
+
+		hookTransformResult := transformOutgoingWebhookMessage(webhookMsg)
+		require.NoError(t, hookTransformResult.Error)
+		require.Equal(t, []bitriseapi.TriggerAPIParamsModel{
+			{
+				BuildParams: bitriseapi.BuildParamsModel{
+					Branch:     "main",
+					WorkflowID: "deploy",
+				},
+			},
+		}, hookTransformResult.TriggerAPIParams)
+	}
+
+	t.Log("Repeated env parameters")
+	{
+		webhookMsg := MessageModel{
+			TriggerText: "bitrise -",
+			Text:        "bitrise - env: DEPLOY_TARGET=staging | env: BUILD_NUMBER=42 | branch: main | workflow: deploy",
+		}
+
+		hookTransformResult := transformOutgoingWebhookMessage(webhookMsg)
+		require.NoError(t, hookTransformResult.Error)
+		require.Equal(t, []bitriseapi.TriggerAPIParamsModel{
+			{
+				BuildParams: bitriseapi.BuildParamsModel{
+					Branch:     "main",
+					WorkflowID: "deploy",
+					Environments: []bitriseapi.EnvironmentItem{
+						{MappedTo: "DEPLOY_TARGET", Value: "staging", IsExpand: true},
+						{MappedTo: "BUILD_NUMBER", Value: "42", IsExpand: true},
+					},
+				},
+			},
+		}, hookTransformResult.TriggerAPIParams)
+	}
+
+	t.Log("env value containing '='")
+	{
+		webhookMsg := MessageModel{
+			TriggerText: "bitrise -",
+			Text:        "bitrise - branch: main | env: QUERY=a=b",
+		}
+
+		hookTransformResult := transformOutgoingWebhookMessage(webhookMsg)
+		require.NoError(t, hookTransformResult.Error)
+		require.Equal(t, []bitriseapi.EnvironmentItem{
+			{MappedTo: "QUERY", Value: "a=b", IsExpand: true},
+		}, hookTransformResult.TriggerAPIParams[0].BuildParams.Environments)
+	}
+
+	t.Log("Unknown key is rejected")
+	{
+		webhookMsg := MessageModel{
+			TriggerText: "bitrise -",
+			Text:        "bitrise - branch: main | bracnh: typo",
+		}
+
+		hookTransformResult := transformOutgoingWebhookMessage(webhookMsg)
+		require.EqualError(t, hookTransformResult.Error, "Unknown parameter: 'bracnh'")
+		require.Nil(t, hookTransformResult.TriggerAPIParams)
+	}
+}
@@ -0,0 +1,123 @@
+// Package telegram implements the hookCommon.Provider interface for a
+// Telegram bot relay that forwards chat messages using the same
+// pipe-separated trigger syntax as the slack package.
+package telegram
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-webhooks/bitriseapi"
+	hookCommon "github.com/bitrise-io/bitrise-webhooks/service/hook/common"
+	"github.com/bitrise-io/bitrise-webhooks/service/hook/common/textparams"
+	"github.com/bitrise-io/bitrise-webhooks/service/hook/common/webhookform"
+)
+
+// SendMessageRespModel is the body Telegram Bot API's `sendMessage` method
+// expects: https://core.telegram.org/bots/api#sendmessage
+type SendMessageRespModel struct {
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode"`
+}
+
+var markdownV2EscapeReplacer = strings.NewReplacer(
+	"_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+	"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`,
+	"=", `\=`, "|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+func escapeMarkdownV2(text string) string {
+	return markdownV2EscapeReplacer.Replace(text)
+}
+
+// HookProvider ...
+type HookProvider struct{}
+
+// TransformRequest ...
+func (hp HookProvider) TransformRequest(r *http.Request) hookCommon.TransformResultModel {
+	contentType, err := webhookform.DetectContentType(r.Header)
+	if err != nil {
+		return hookCommon.TransformResultModel{Error: err}
+	}
+	if contentType != "application/x-www-form-urlencoded" {
+		return hookCommon.TransformResultModel{
+			Error: fmt.Errorf("Content-Type is not supported: %s", contentType),
+		}
+	}
+
+	messageModel, err := webhookform.ParseMessage(r)
+	if err != nil {
+		return hookCommon.TransformResultModel{
+			Error: fmt.Errorf("Failed to parse the request/message: %s", err),
+		}
+	}
+
+	return textparams.Transform(messageModel.TriggerText, messageModel.Text)
+}
+
+// telegramConvertor renders Trigger API results as a MarkdownV2 message for
+// the Telegram Bot API's `sendMessage` method.
+type telegramConvertor struct{}
+
+func (telegramConvertor) Success(resp bitriseapi.TriggerAPIResponseModel) interface{} {
+	return escapeMarkdownV2(fmt.Sprintf("%+v", resp))
+}
+
+func (telegramConvertor) Failure(resp bitriseapi.TriggerAPIResponseModel) interface{} {
+	return escapeMarkdownV2(fmt.Sprintf("%+v", resp))
+}
+
+func (telegramConvertor) Error(errMsg string) interface{} {
+	return escapeMarkdownV2(errMsg)
+}
+
+func (telegramConvertor) Wrap(successes, faileds, errs []interface{}) (interface{}, string) {
+	lines := []string{"*Results:*"}
+
+	appendSection := func(heading string, parts []interface{}) {
+		if len(parts) == 0 {
+			return
+		}
+		lines = append(lines, fmt.Sprintf("*%s:*", escapeMarkdownV2(heading)))
+		for _, aPart := range parts {
+			lines = append(lines, "• "+aPart.(string))
+		}
+	}
+
+	appendSection("Success", successes)
+	appendSection("Failed Triggers", faileds)
+	appendSection("Errors", errs)
+
+	return SendMessageRespModel{
+		Text:      strings.Join(lines, "\n"),
+		ParseMode: "MarkdownV2",
+	}, "application/json"
+}
+
+// TransformResponse ...
+func (hp HookProvider) TransformResponse(input hookCommon.TransformResponseInputModel) hookCommon.TransformResponseModel {
+	return hookCommon.BuildTransformResponse(telegramConvertor{}, input)
+}
+
+// TransformErrorMessageResponse ...
+func (hp HookProvider) TransformErrorMessageResponse(errMsg string) hookCommon.TransformResponseModel {
+	return hookCommon.TransformResponseModel{
+		Data: SendMessageRespModel{
+			Text:      fmt.Sprintf("*Error:* %s", escapeMarkdownV2(errMsg)),
+			ParseMode: "MarkdownV2",
+		},
+		HTTPStatusCode: http.StatusOK,
+	}
+}
+
+// TransformSuccessMessageResponse ...
+func (hp HookProvider) TransformSuccessMessageResponse(msg string) hookCommon.TransformResponseModel {
+	return hookCommon.TransformResponseModel{
+		Data: SendMessageRespModel{
+			Text:      escapeMarkdownV2(msg),
+			ParseMode: "MarkdownV2",
+		},
+		HTTPStatusCode: http.StatusOK,
+	}
+}
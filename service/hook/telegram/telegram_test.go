@@ -0,0 +1,46 @@
+package telegram
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-webhooks/bitriseapi"
+	hookCommon "github.com/bitrise-io/bitrise-webhooks/service/hook/common"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HookProvider_TransformRequest(t *testing.T) {
+	provider := HookProvider{}
+
+	request := http.Request{
+		Header: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+	}
+	form := url.Values{}
+	form.Add("trigger_word", "bitrise:")
+	form.Add("text", "bitrise: branch:master")
+	request.PostForm = form
+
+	hookTransformResult := provider.TransformRequest(&request)
+	require.NoError(t, hookTransformResult.Error)
+	require.Equal(t, []bitriseapi.TriggerAPIParamsModel{
+		{BuildParams: bitriseapi.BuildParamsModel{Branch: "master"}},
+	}, hookTransformResult.TriggerAPIParams)
+}
+
+func Test_escapeMarkdownV2(t *testing.T) {
+	require.Equal(t, `Build \#42\.`, escapeMarkdownV2("Build #42."))
+}
+
+func Test_HookProvider_TransformResponse(t *testing.T) {
+	provider := HookProvider{}
+
+	resp := provider.TransformResponse(hookCommon.TransformResponseInputModel{
+		Errors: []string{"a.b"},
+	})
+
+	respModel, ok := resp.Data.(SendMessageRespModel)
+	require.True(t, ok)
+	require.Equal(t, "MarkdownV2", respModel.ParseMode)
+	require.Contains(t, respModel.Text, `a\.b`)
+}